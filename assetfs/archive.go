@@ -0,0 +1,499 @@
+package assetfs
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveMagic starts every archive and includes the format version
+var archiveMagic = [8]byte{'I', 'G', 'L', 'O', 'O', 'P', 'K', '1'}
+
+// header is the magic, index length and index hash
+const archiveHeaderSize = 8 + 8 + sha256.Size
+
+var (
+	// ErrCorrupt is returned when archive contents do not match their hashes
+	ErrCorrupt = errors.New("archive corrupt")
+	// ErrNotArchive is returned when opening something that is not an archive
+	ErrNotArchive = errors.New("not an igloo archive")
+)
+
+type archiveEntry struct {
+	Path    string    `json:"path"`
+	Offset  int64     `json:"offset"`
+	Packed  int64     `json:"packed"`
+	Size    int64     `json:"size"`
+	Hash    string    `json:"hash"`
+	ModTime time.Time `json:"modTime"`
+}
+
+type archiveOptions struct {
+	modTimes bool
+}
+
+type ArchiveOption func(o *archiveOptions)
+
+// ArchiveWithModTimes keeps the modification time of every file,
+// archives are then different each time files are checked out or copied.
+func ArchiveWithModTimes() ArchiveOption {
+	return func(o *archiveOptions) {
+		o.modTimes = true
+	}
+}
+
+// WriteArchive packs every regular file in src into a single archive.
+// Files are compressed individually with flate at level and indexed with a
+// sha256 hash of their uncompressed contents.
+// Modification times are left out unless ArchiveWithModTimes is used,
+// so packing the same files always writes the same archive.
+func WriteArchive(w io.Writer, src fs.FS, level int, options ...ArchiveOption) error {
+	var (
+		entries []archiveEntry
+		data    bytes.Buffer
+		opts    archiveOptions
+	)
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	err := fs.WalkDir(src, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		entry, err := packArchiveEntry(&data, src, d, name, level, opts)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("packing archive: %w", err)
+	}
+
+	index, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("writing archive index: %w", err)
+	}
+
+	header := make([]byte, archiveHeaderSize)
+	indexHash := sha256.Sum256(index)
+
+	copy(header, archiveMagic[:])
+	binary.LittleEndian.PutUint64(header[8:], uint64(len(index)))
+	copy(header[16:], indexHash[:])
+
+	for _, chunk := range [][]byte{header, index, data.Bytes()} {
+		if _, err := w.Write(chunk); err != nil {
+			return fmt.Errorf("writing archive: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// packArchiveEntry compresses a file onto the end of data
+func packArchiveEntry(
+	data *bytes.Buffer,
+	src fs.FS,
+	d fs.DirEntry,
+	name string,
+	level int,
+	opts archiveOptions,
+) (archiveEntry, error) {
+	var entry archiveEntry
+
+	contents, err := fs.ReadFile(src, name)
+	if err != nil {
+		return entry, err
+	}
+
+	offset := int64(data.Len())
+
+	compressor, err := flate.NewWriter(data, level)
+	if err != nil {
+		return entry, err
+	}
+
+	if _, err = compressor.Write(contents); err != nil {
+		return entry, err
+	}
+
+	if err = compressor.Close(); err != nil {
+		return entry, err
+	}
+
+	hash := sha256.Sum256(contents)
+	entry = archiveEntry{
+		Path:   name,
+		Offset: offset,
+		Packed: int64(data.Len()) - offset,
+		Size:   int64(len(contents)),
+		Hash:   hex.EncodeToString(hash[:]),
+	}
+
+	if opts.modTimes {
+		var info fs.FileInfo
+
+		info, err = d.Info()
+		if err != nil {
+			return entry, err
+		}
+
+		entry.ModTime = info.ModTime().UTC()
+	}
+
+	return entry, nil
+}
+
+// Archive is a read only fs.FS over a packed archive.
+// File contents are verified against their hash every time they are opened.
+type Archive struct {
+	reader     io.ReaderAt
+	closer     io.Closer
+	dataOffset int64
+	files      map[string]archiveEntry
+	dirs       map[string][]fs.DirEntry
+}
+
+// OpenArchiveFile opens an archive on disk, close it once it is no longer used
+func OpenArchiveFile(archivePath string) (*Archive, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	archive, err := OpenArchive(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("opening archive %v: %w", archivePath, err)
+	}
+
+	archive.closer = file
+
+	return archive, nil
+}
+
+// OpenArchive reads the index of an archive of size bytes
+func OpenArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	if size < archiveHeaderSize {
+		return nil, ErrNotArchive
+	}
+
+	header := make([]byte, archiveHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:8], archiveMagic[:]) {
+		return nil, ErrNotArchive
+	}
+
+	indexLen := binary.LittleEndian.Uint64(header[8:])
+	if indexLen > uint64(size-archiveHeaderSize) {
+		return nil, fmt.Errorf("%w: index length", ErrCorrupt)
+	}
+
+	index := make([]byte, indexLen)
+	if _, err := r.ReadAt(index, archiveHeaderSize); err != nil {
+		return nil, err
+	}
+
+	indexHash := sha256.Sum256(index)
+	if !bytes.Equal(indexHash[:], header[16:]) {
+		return nil, fmt.Errorf("%w: index hash", ErrCorrupt)
+	}
+
+	var entries []archiveEntry
+	if err := json.Unmarshal(index, &entries); err != nil {
+		return nil, fmt.Errorf("%w: index: %v", ErrCorrupt, err)
+	}
+
+	archive := &Archive{
+		reader:     r,
+		dataOffset: archiveHeaderSize + int64(indexLen),
+		files:      make(map[string]archiveEntry, len(entries)),
+		dirs:       map[string][]fs.DirEntry{".": nil},
+	}
+
+	for _, entry := range entries {
+		if !fs.ValidPath(entry.Path) || entry.Path == "." {
+			return nil, fmt.Errorf("%w: invalid path %v", ErrCorrupt, entry.Path)
+		}
+
+		if archive.dataOffset+entry.Offset+entry.Packed > size {
+			return nil, fmt.Errorf("%w: %v out of range", ErrCorrupt, entry.Path)
+		}
+
+		archive.files[entry.Path] = entry
+		archive.addDirEntries(entry)
+	}
+
+	for _, dirEntries := range archive.dirs {
+		sort.Slice(dirEntries, func(i, j int) bool {
+			return dirEntries[i].Name() < dirEntries[j].Name()
+		})
+	}
+
+	return archive, nil
+}
+
+// addDirEntries registers the file and every parent directory
+func (a *Archive) addDirEntries(entry archiveEntry) {
+	child := fs.FileInfoToDirEntry(archiveInfo{
+		name:    path.Base(entry.Path),
+		size:    entry.Size,
+		modTime: entry.ModTime,
+	})
+
+	for name := entry.Path; name != "."; {
+		parent := path.Dir(name)
+		_, parentExists := a.dirs[parent]
+
+		a.dirs[parent] = append(a.dirs[parent], child)
+
+		if parentExists {
+			return
+		}
+
+		child = fs.FileInfoToDirEntry(archiveInfo{name: path.Base(parent), isDir: true})
+		name = parent
+	}
+}
+
+// Close the underlying file if opened with OpenArchiveFile
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+
+	return a.closer.Close()
+}
+
+// Paths returns every file path in the archive, sorted
+func (a *Archive) Paths() []string {
+	paths := make([]string, 0, len(a.files))
+	for name := range a.files {
+		paths = append(paths, name)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+func (a *Archive) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if entry, ok := a.files[name]; ok {
+		contents, err := a.read(entry)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+
+		return &archiveFile{
+			Reader: bytes.NewReader(contents),
+			info: archiveInfo{
+				name:    path.Base(name),
+				size:    entry.Size,
+				modTime: entry.ModTime,
+			},
+		}, nil
+	}
+
+	if entries, ok := a.dirs[name]; ok {
+		return &archiveDir{
+			info:    archiveInfo{name: path.Base(name), isDir: true},
+			entries: entries,
+		}, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entry, ok := a.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	contents, err := a.read(entry)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return contents, nil
+}
+
+func (a *Archive) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if entry, ok := a.files[name]; ok {
+		return archiveInfo{name: path.Base(name), size: entry.Size, modTime: entry.ModTime}, nil
+	}
+
+	if _, ok := a.dirs[name]; ok {
+		return archiveInfo{name: path.Base(name), isDir: true}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (a *Archive) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries, ok := a.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]fs.DirEntry(nil), entries...), nil
+}
+
+// read decompresses and verifies the contents of an entry
+func (a *Archive) read(entry archiveEntry) ([]byte, error) {
+	section := io.NewSectionReader(a.reader, a.dataOffset+entry.Offset, entry.Packed)
+	decompressor := flate.NewReader(section)
+
+	defer decompressor.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(decompressor, entry.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	if int64(len(contents)) != entry.Size {
+		return nil, fmt.Errorf("%w: size mismatch", ErrCorrupt)
+	}
+
+	hash := sha256.Sum256(contents)
+	if !strings.EqualFold(hex.EncodeToString(hash[:]), entry.Hash) {
+		return nil, fmt.Errorf("%w: hash mismatch", ErrCorrupt)
+	}
+
+	return contents, nil
+}
+
+type archiveInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (i archiveInfo) Name() string {
+	return i.name
+}
+
+func (i archiveInfo) Size() int64 {
+	return i.size
+}
+
+func (i archiveInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
+
+func (i archiveInfo) ModTime() time.Time {
+	return i.modTime
+}
+
+func (i archiveInfo) IsDir() bool {
+	return i.isDir
+}
+
+func (i archiveInfo) Sys() any {
+	return nil
+}
+
+type archiveFile struct {
+	*bytes.Reader
+	info archiveInfo
+}
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *archiveFile) Close() error {
+	return nil
+}
+
+type archiveDir struct {
+	info    archiveInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *archiveDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *archiveDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *archiveDir) Close() error {
+	return nil
+}
+
+func (d *archiveDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return append([]fs.DirEntry(nil), remaining...), nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+
+	d.offset += count
+
+	return append([]fs.DirEntry(nil), remaining[:count]...), nil
+}
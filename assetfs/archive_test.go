@@ -0,0 +1,122 @@
+package assetfs_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/miniscruff/igloo/assetfs"
+)
+
+func packTestArchive(t *testing.T) []byte {
+	t.Helper()
+
+	src := fstest.MapFS{
+		"assets/hero.png":        {Data: []byte("hero pixels")},
+		"assets/fonts/main.fnt":  {Data: []byte("info face=main")},
+		"assets/levels/one.json": {Data: bytes.Repeat([]byte("tile "), 100)},
+	}
+
+	var buf bytes.Buffer
+
+	err := assetfs.WriteArchive(&buf, src, flate.BestCompression)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestArchiveFS(t *testing.T) {
+	data := packTestArchive(t)
+
+	archive, err := assetfs.OpenArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = fstest.TestFS(archive, "assets/hero.png", "assets/fonts/main.fnt", "assets/levels/one.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fs.ReadFile(archive, "assets/hero.png")
+	if err != nil || string(got) != "hero pixels" {
+		t.Fatalf("expected: hero pixels, got: %v %v", string(got), err)
+	}
+}
+
+func TestArchiveCorrupt(t *testing.T) {
+	data := packTestArchive(t)
+
+	// flip a bit in the last byte of the data section
+	data[len(data)-1] ^= 0x01
+
+	archive, err := assetfs.OpenArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var corrupted int
+
+	for _, name := range archive.Paths() {
+		if _, err := fs.ReadFile(archive, name); errors.Is(err, assetfs.ErrCorrupt) {
+			corrupted++
+		}
+	}
+
+	if corrupted != 1 {
+		t.Fatalf("expected 1 corrupt file, got: %v", corrupted)
+	}
+}
+
+func TestArchiveNotArchive(t *testing.T) {
+	data := []byte("definitely not an archive, just some text")
+
+	_, err := assetfs.OpenArchive(bytes.NewReader(data), int64(len(data)))
+	if !errors.Is(err, assetfs.ErrNotArchive) {
+		t.Fatalf("expected not archive error, got: %v", err)
+	}
+}
+
+func TestArchiveModTimes(t *testing.T) {
+	packAt := func(modTime time.Time, options ...assetfs.ArchiveOption) []byte {
+		var buf bytes.Buffer
+
+		src := fstest.MapFS{
+			"assets/hero.png": {Data: []byte("hero pixels"), ModTime: modTime},
+		}
+
+		if err := assetfs.WriteArchive(&buf, src, flate.BestCompression, options...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return buf.Bytes()
+	}
+
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	if !bytes.Equal(packAt(first), packAt(second)) {
+		t.Fatal("expected: the same archive without mod times")
+	}
+
+	data := packAt(first, assetfs.ArchiveWithModTimes())
+	if bytes.Equal(data, packAt(second, assetfs.ArchiveWithModTimes())) {
+		t.Fatal("expected: different archives with mod times")
+	}
+
+	archive, err := assetfs.OpenArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := fs.Stat(archive, "assets/hero.png")
+	if err != nil || !info.ModTime().Equal(first) {
+		t.Fatalf("expected: %v, got: %v %v", first, info, err)
+	}
+}
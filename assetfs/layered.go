@@ -0,0 +1,198 @@
+package assetfs
+
+import (
+	"archive/zip"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Layer is a named file system inside of a LayeredFS
+type Layer struct {
+	Name string
+	FS   fs.FS
+}
+
+// DirLayer creates a layer from a directory on disk, such as a mod folder
+func DirLayer(name, dir string) Layer {
+	return Layer{
+		Name: name,
+		FS:   os.DirFS(dir),
+	}
+}
+
+// ZipLayer opens a zip file on disk as a layer, such as a DLC or patch pack.
+// The returned closer should be closed once the layer is no longer used.
+func ZipLayer(name, zipPath string) (Layer, *zip.ReadCloser, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return Layer{}, nil, err
+	}
+
+	return Layer{Name: name, FS: reader}, reader, nil
+}
+
+// LayeredFS merges multiple file systems where later layers override
+// earlier layers by path. Opening a directory returns the top most layer
+// that has it, while ReadDir merges the entries of every layer.
+// Layers can be pushed while files are being opened from other goroutines.
+type LayeredFS struct {
+	layersMu sync.RWMutex
+	layers   []Layer
+
+	mu     sync.Mutex
+	served map[string]string
+}
+
+// NewLayeredFS creates a file system from layers ordered lowest priority first
+func NewLayeredFS(layers ...Layer) *LayeredFS {
+	return &LayeredFS{
+		layers: layers,
+		served: make(map[string]string),
+	}
+}
+
+// Push adds a new layer on top of all existing layers
+func (l *LayeredFS) Push(layer Layer) {
+	l.layersMu.Lock()
+	defer l.layersMu.Unlock()
+
+	// copy our layers so readers can keep using the layers they already have
+	layers := make([]Layer, len(l.layers), len(l.layers)+1)
+	copy(layers, l.layers)
+	l.layers = append(layers, layer)
+}
+
+// Layers returns a copy of our layers ordered lowest priority first
+func (l *LayeredFS) Layers() []Layer {
+	return append([]Layer(nil), l.currentLayers()...)
+}
+
+// currentLayers returns our layers at this moment, the slice is never
+// modified after it is replaced by Push.
+func (l *LayeredFS) currentLayers() []Layer {
+	l.layersMu.RLock()
+	defer l.layersMu.RUnlock()
+
+	return l.layers
+}
+
+// LayerOf returns the name of the layer that would serve name
+func (l *LayeredFS) LayerOf(name string) (string, bool) {
+	if !fs.ValidPath(name) {
+		return "", false
+	}
+
+	layers := l.currentLayers()
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		if _, err := fs.Stat(layers[i].FS, name); err == nil {
+			return layers[i].Name, true
+		}
+	}
+
+	return "", false
+}
+
+// Served returns a copy of every path opened so far and the layer that served it
+func (l *LayeredFS) Served() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	served := make(map[string]string, len(l.served))
+	for name, layer := range l.served {
+		served[name] = layer
+	}
+
+	return served
+}
+
+func (l *LayeredFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	layers := l.currentLayers()
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		file, err := layers[i].FS.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		l.mu.Lock()
+		l.served[name] = layers[i].Name
+		l.mu.Unlock()
+
+		return file, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l *LayeredFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	layers := l.currentLayers()
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		info, err := fs.Stat(layers[i].FS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return info, err
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir merges the entries of every layer, sorted by name
+func (l *LayeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	merged := make(map[string]fs.DirEntry)
+	found := false
+
+	for _, layer := range l.currentLayers() {
+		entries, err := fs.ReadDir(layer.FS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		for _, entry := range entries {
+			merged[entry.Name()] = entry
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
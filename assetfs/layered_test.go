@@ -0,0 +1,140 @@
+package assetfs_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/miniscruff/igloo/assetfs"
+)
+
+func newTestLayeredFS() *assetfs.LayeredFS {
+	return assetfs.NewLayeredFS(
+		assetfs.Layer{
+			Name: "base",
+			FS: fstest.MapFS{
+				"assets/hero.png":  {Data: []byte("base hero")},
+				"assets/enemy.png": {Data: []byte("base enemy")},
+			},
+		},
+		assetfs.Layer{
+			Name: "mod",
+			FS: fstest.MapFS{
+				"assets/hero.png": {Data: []byte("mod hero")},
+				"assets/boss.png": {Data: []byte("mod boss")},
+			},
+		},
+	)
+}
+
+func TestLayeredFSOverrides(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		expected string
+		layer    string
+	}{
+		"overridden": {
+			path:     "assets/hero.png",
+			expected: "mod hero",
+			layer:    "mod",
+		},
+		"base only": {
+			path:     "assets/enemy.png",
+			expected: "base enemy",
+			layer:    "base",
+		},
+		"mod only": {
+			path:     "assets/boss.png",
+			expected: "mod boss",
+			layer:    "mod",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := newTestLayeredFS()
+
+			got, err := fs.ReadFile(fsys, tc.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(got) != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, string(got))
+			}
+
+			layer, ok := fsys.LayerOf(tc.path)
+			if !ok || layer != tc.layer {
+				t.Fatalf("expected layer: %v, got: %v", tc.layer, layer)
+			}
+
+			if served := fsys.Served()[tc.path]; served != tc.layer {
+				t.Fatalf("expected served by: %v, got: %v", tc.layer, served)
+			}
+		})
+	}
+}
+
+func TestLayeredFSInvalidPath(t *testing.T) {
+	fsys := newTestLayeredFS()
+
+	_, err := fsys.Open("../assets/hero.png")
+	if !errors.Is(err, fs.ErrInvalid) {
+		t.Fatalf("expected invalid error, got: %v", err)
+	}
+
+	_, err = fsys.Open("assets/missing.png")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+}
+
+func TestLayeredFSReadDir(t *testing.T) {
+	entries, err := fs.ReadDir(newTestLayeredFS(), "assets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"boss.png", "enemy.png", "hero.png"}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v entries, got: %v", len(expected), len(entries))
+	}
+
+	for i, entry := range entries {
+		if entry.Name() != expected[i] {
+			t.Fatalf("expected entry %v: %v, got: %v", i, expected[i], entry.Name())
+		}
+	}
+}
+
+func TestLayeredFSPushWhileOpening(t *testing.T) {
+	fsys := newTestLayeredFS()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			if _, err := fs.ReadFile(fsys, "assets/hero.png"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		fsys.Push(assetfs.Layer{Name: "patch", FS: fstest.MapFS{}})
+	}
+
+	<-done
+
+	if layers := fsys.Layers(); len(layers) != 102 {
+		t.Fatalf("expected: 102 layers, got: %v", len(layers))
+	}
+
+	got, err := fs.ReadFile(fsys, "assets/hero.png")
+	if err != nil || string(got) != "mod hero" {
+		t.Fatalf("expected: mod hero, got: %v, %v", string(got), err)
+	}
+}
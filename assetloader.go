@@ -4,13 +4,21 @@ import (
 	"fmt"
 	"image"
 	"io/fs"
+	"path"
 
-	// import png for image loading
+	// import image formats for image loading
+	_ "image/gif"
+	_ "image/jpeg"
 	_ "image/png"
 
-	"golang.org/x/image/font/opentype"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+
+	"github.com/miniscruff/igloo/content"
 )
 
 // ContentManager handles content loading, unloading, caching
@@ -18,21 +26,69 @@ import (
 type AssetLoader struct {
 	fsys    fs.FS
 	rootDir string
+
+	openTypes map[string]*opentype.Font
+	faces     map[fontFaceKey]font.Face
+	packer    *AtlasPacker
+	data      map[string]*dataAsset
+	reloaded  EventStoreOne[string]
 }
 
 func NewAssetLoader(fsys fs.FS, rootDir string) *AssetLoader {
 	return &AssetLoader{
-		fsys:    fsys,
-		rootDir: rootDir,
+		fsys:      fsys,
+		rootDir:   rootDir,
+		openTypes: make(map[string]*opentype.Font),
+		faces:     make(map[fontFaceKey]font.Face),
+		packer:    NewAtlasPacker(),
+		data:      make(map[string]*dataAsset),
 	}
 }
 
-func (a *AssetLoader) fullPath(path string) string {
-	return a.rootDir + "/" + path
+// SetAtlasPacker replaces the packer used by LoadPackedSprite
+func (a *AssetLoader) SetAtlasPacker(packer *AtlasPacker) {
+	a.packer = packer
+}
+
+// fullPath joins our root directory to an asset path, asset paths
+// must be valid fs paths so they can not escape the root directory.
+func (a *AssetLoader) fullPath(assetPath string) (string, error) {
+	if !fs.ValidPath(assetPath) {
+		return "", &fs.PathError{Op: "open", Path: assetPath, Err: fs.ErrInvalid}
+	}
+
+	fullPath := path.Join(a.rootDir, assetPath)
+	if !fs.ValidPath(fullPath) {
+		return "", &fs.PathError{Op: "open", Path: fullPath, Err: fs.ErrInvalid}
+	}
+
+	return fullPath, nil
+}
+
+// AssetLayer returns the name of the layer serving an asset path when our
+// file system is layered, such as an assetfs.LayeredFS.
+func (a *AssetLoader) AssetLayer(assetPath string) (string, bool) {
+	layered, ok := a.fsys.(interface {
+		LayerOf(name string) (string, bool)
+	})
+	if !ok {
+		return "", false
+	}
+
+	fullPath, err := a.fullPath(assetPath)
+	if err != nil {
+		return "", false
+	}
+
+	return layered.LayerOf(fullPath)
 }
 
 func (a *AssetLoader) readFSFile(path string) ([]byte, error) {
-	fullPath := a.fullPath(path)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	fileBytes, err := fs.ReadFile(a.fsys, fullPath)
 
 	if err != nil {
@@ -46,8 +102,16 @@ func (a *AssetLoader) readFSFile(path string) ([]byte, error) {
 	return fileBytes, nil
 }
 
-func (a *AssetLoader) LoadImage(path string) (*ebiten.Image, error) {
-	fullPath := a.fullPath(path)
+// ReadFile reads the raw bytes of an asset, empty files are an error
+func (a *AssetLoader) ReadFile(path string) ([]byte, error) {
+	return a.readFSFile(path)
+}
+
+func (a *AssetLoader) decodeImage(path string) (image.Image, error) {
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	file, err := a.fsys.Open(fullPath)
 	if err != nil {
@@ -61,11 +125,73 @@ func (a *AssetLoader) LoadImage(path string) (*ebiten.Image, error) {
 		return nil, err
 	}
 
-	ebiImage := ebiten.NewImageFromImage(img)
+	return img, nil
+}
+
+// LoadImage loads a png, jpeg, gif, bmp or webp image.
+// For animated gifs only the first frame is loaded, see LoadGIF.
+func (a *AssetLoader) LoadImage(path string, options ...ImageOption) (*ebiten.Image, error) {
+	img, err := a.decodeImage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ebiImage := ebiten.NewImageFromImage(newImageOptions(options).apply(img))
 
 	return ebiImage, nil
 }
 
+// LoadPackedSprite loads an image into a shared atlas page, sprites loaded
+// this way share source images and can be batched together.
+func (a *AssetLoader) LoadPackedSprite(
+	path string,
+	options ...ImageOption,
+) (*content.Sprite, error) {
+	img, err := a.decodeImage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.packer.Pack(path, newImageOptions(options).apply(img))
+}
+
+// LoadShader compiles a Kage shader, compile errors include the shader path
+func (a *AssetLoader) LoadShader(path string) (*ebiten.Shader, error) {
+	shaderBytes, err := a.readFSFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	shader, err := ebiten.NewShader(shaderBytes)
+	if err != nil {
+		return nil, fmt.Errorf("compiling shader %v: %w", path, err)
+	}
+
+	return shader, nil
+}
+
+// LoadParticleEmitter loads a json or yaml particle emitter definition
+// along with its image, image paths are relative to the definition.
+func (a *AssetLoader) LoadParticleEmitter(emitterPath string) (*content.ParticleEmitterDef, error) {
+	def, err := decodeData[content.ParticleEmitterDef](a, emitterPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Image != "" {
+		var img *ebiten.Image
+
+		img, err = a.LoadImage(path.Join(path.Dir(emitterPath), def.Image))
+		if err != nil {
+			return nil, fmt.Errorf("loading particle image: %w", err)
+		}
+
+		def.Sprite = &content.Sprite{Image: img}
+	}
+
+	return def, nil
+}
+
 func (a *AssetLoader) LoadOpenType(path string) (*opentype.Font, error) {
 	fontBytes, err := a.readFSFile(path)
 	if err != nil {
@@ -79,3 +205,117 @@ func (a *AssetLoader) LoadOpenType(path string) (*opentype.Font, error) {
 
 	return openType, nil
 }
+
+// FontOptions configures the face and rendering of a loaded font
+type FontOptions struct {
+	// Size in points, required
+	Size float64
+	// DPI defaults to 72 so that size matches pixels
+	DPI     float64
+	Hinting font.Hinting
+	ebiten.Filter
+	ebiten.CompositeMode
+}
+
+type fontFaceKey struct {
+	path    string
+	size    float64
+	dpi     float64
+	hinting font.Hinting
+}
+
+// LoadFont loads an opentype font and creates a face ready to draw with.
+// Faces are cached by path, size, DPI and hinting so fonts loaded with the
+// same options share a glyph cache.
+func (a *AssetLoader) LoadFont(path string, options FontOptions) (*content.Font, error) {
+	if options.Size <= 0 {
+		return nil, fmt.Errorf("loading font %v: size must be positive", path)
+	}
+
+	if options.DPI <= 0 {
+		options.DPI = 72
+	}
+
+	key := fontFaceKey{
+		path:    path,
+		size:    options.Size,
+		dpi:     options.DPI,
+		hinting: options.Hinting,
+	}
+
+	face, ok := a.faces[key]
+	if !ok {
+		openType, err := a.cachedOpenType(path)
+		if err != nil {
+			return nil, err
+		}
+
+		face, err = opentype.NewFace(openType, &opentype.FaceOptions{
+			Size:    options.Size,
+			DPI:     options.DPI,
+			Hinting: options.Hinting,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating font face %v: %w", path, err)
+		}
+
+		a.faces[key] = face
+	}
+
+	return &content.Font{
+		Face:          face,
+		Filter:        options.Filter,
+		CompositeMode: options.CompositeMode,
+	}, nil
+}
+
+func (a *AssetLoader) cachedOpenType(path string) (*opentype.Font, error) {
+	if openType, ok := a.openTypes[path]; ok {
+		return openType, nil
+	}
+
+	openType, err := a.LoadOpenType(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.openTypes[path] = openType
+
+	return openType, nil
+}
+
+// LoadBitmapFont loads an AngelCode BMFont in text or XML format along with
+// its page images. Page files are relative to the font file.
+func (a *AssetLoader) LoadBitmapFont(fontPath string) (*content.Font, error) {
+	fontBytes, err := a.readFSFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := content.ParseBitmapFont(fontBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing bitmap font %v: %w", fontPath, err)
+	}
+
+	pages := make([]image.Image, len(def.Pages))
+
+	for _, page := range def.Pages {
+		if page.ID < 0 || page.ID >= len(pages) {
+			return nil, fmt.Errorf("bitmap font %v has invalid page id %v", fontPath, page.ID)
+		}
+
+		pages[page.ID], err = a.decodeImage(path.Join(path.Dir(fontPath), page.File))
+		if err != nil {
+			return nil, fmt.Errorf("loading bitmap font page %v: %w", page.File, err)
+		}
+	}
+
+	face, err := content.NewBitmapFace(def, pages)
+	if err != nil {
+		return nil, fmt.Errorf("creating bitmap font %v: %w", fontPath, err)
+	}
+
+	return &content.Font{
+		Face: face,
+	}, nil
+}
@@ -0,0 +1,216 @@
+package igloo_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/miniscruff/igloo"
+)
+
+func TestLoadShader(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expectErr bool
+	}{
+		"valid": {
+			path: "flash.kage",
+		},
+		"compile error": {
+			path:      "broken.kage",
+			expectErr: true,
+		},
+		"missing": {
+			path:      "missing.kage",
+			expectErr: true,
+		},
+	}
+
+	fsys := fstest.MapFS{
+		"assets/flash.kage": {Data: []byte(`package main
+
+var Amount float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return mix(imageSrc0At(texCoord), vec4(1), Amount)
+}
+`)},
+		"assets/broken.kage": {Data: []byte("package main\n\nfunc Fragment(")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := igloo.NewAssetLoader(fsys, "assets")
+
+			shader, err := loader.LoadShader(tc.path)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil || shader == nil {
+				t.Fatalf("expected: shader, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadParticleEmitter(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expectErr bool
+	}{
+		"valid": {
+			path: "particles/sparks.json",
+		},
+		"unknown ease": {
+			path:      "particles/ease.json",
+			expectErr: true,
+		},
+		"missing image": {
+			path:      "particles/missing.json",
+			expectErr: true,
+		},
+		"zero lifetime": {
+			path:      "particles/instant.json",
+			expectErr: true,
+		},
+	}
+
+	fsys := fstest.MapFS{
+		"assets/particles/spark.png": {Data: tiledTilesPNG(t)},
+		"assets/particles/sparks.json": {Data: []byte(`{"image": "spark.png",
+ "shape": "circle", "width": 8, "height": 8, "rate": 10,
+ "bursts": [{"time": 1, "count": 5}, {"time": 0, "count": 20}],
+ "lifetime": {"min": 0.5, "max": 1}, "gravity": {"x": 0, "y": 98},
+ "size": {"start": 1, "end": 0, "ease": "inQuad"},
+ "rotationCurve": {"start": 0, "end": 3.14, "ease": "outQuad"}}`)},
+		"assets/particles/ease.json": {Data: []byte(`{"lifetime": {"min": 1, "max": 1},
+ "size": {"start": 1, "end": 0, "ease": "wobbly"}}`)},
+		"assets/particles/missing.json": {Data: []byte(`{"image": "missing.png",
+ "lifetime": {"min": 1, "max": 1}}`)},
+		"assets/particles/instant.json": {Data: []byte(`{"lifetime": {"min": 0, "max": 1}}`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := igloo.NewAssetLoader(fsys, "assets")
+
+			def, err := loader.LoadParticleEmitter(tc.path)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if def.Sprite == nil || def.Gravity.Y != 98 {
+				t.Fatalf("expected: sprite and gravity, got: %+v", def)
+			}
+
+			// bursts are sorted by time
+			if def.Bursts[0].Count != 20 {
+				t.Fatalf("expected: 20, got: %v", def.Bursts[0].Count)
+			}
+		})
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/regular.ttf": {Data: goregular.TTF},
+		"assets/broken.ttf":  {Data: []byte("not a font")},
+	}
+
+	loader := igloo.NewAssetLoader(fsys, "assets")
+
+	base, err := loader.LoadFont("regular.ttf", igloo.FontOptions{Size: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		path      string
+		options   igloo.FontOptions
+		sameFace  bool
+		expectErr bool
+	}{
+		"same options": {
+			path:     "regular.ttf",
+			options:  igloo.FontOptions{Size: 12},
+			sameFace: true,
+		},
+		"default dpi": {
+			path:     "regular.ttf",
+			options:  igloo.FontOptions{Size: 12, DPI: 72},
+			sameFace: true,
+		},
+		"different filter": {
+			path:     "regular.ttf",
+			options:  igloo.FontOptions{Size: 12, Filter: ebiten.FilterLinear},
+			sameFace: true,
+		},
+		"different size": {
+			path:    "regular.ttf",
+			options: igloo.FontOptions{Size: 24},
+		},
+		"different dpi": {
+			path:    "regular.ttf",
+			options: igloo.FontOptions{Size: 12, DPI: 96},
+		},
+		"different hinting": {
+			path:    "regular.ttf",
+			options: igloo.FontOptions{Size: 12, Hinting: font.HintingFull},
+		},
+		"missing size": {
+			path:      "regular.ttf",
+			expectErr: true,
+		},
+		"missing file": {
+			path:      "missing.ttf",
+			options:   igloo.FontOptions{Size: 12},
+			expectErr: true,
+		},
+		"broken file": {
+			path:      "broken.ttf",
+			options:   igloo.FontOptions{Size: 12},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loaded, err := loader.LoadFont(tc.path, tc.options)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (loaded.Face == base.Face) != tc.sameFace {
+				t.Fatalf("expected: same face %v, got: %v", tc.sameFace, !tc.sameFace)
+			}
+
+			if loaded.Filter != tc.options.Filter {
+				t.Fatalf("expected: %v, got: %v", tc.options.Filter, loaded.Filter)
+			}
+		})
+	}
+}
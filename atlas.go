@@ -0,0 +1,213 @@
+package igloo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+type atlasRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type atlasFrame struct {
+	Filename         string     `json:"filename"`
+	Frame            atlasRect  `json:"frame"`
+	Rotated          bool       `json:"rotated"`
+	Trimmed          bool       `json:"trimmed"`
+	SpriteSourceSize atlasRect  `json:"spriteSourceSize"`
+	SourceSize       atlasRect  `json:"sourceSize"`
+	Pivot            mathf.Vec2 `json:"pivot"`
+	Duration         float64    `json:"duration"`
+}
+
+type atlasFrameTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+}
+
+type atlasMeta struct {
+	App       string          `json:"app"`
+	Image     string          `json:"image"`
+	FrameTags []atlasFrameTag `json:"frameTags"`
+}
+
+type atlasFile struct {
+	Frames     json.RawMessage     `json:"frames"`
+	Meta       atlasMeta           `json:"meta"`
+	Animations map[string][]string `json:"animations"`
+}
+
+// LoadAtlas loads a TexturePacker JSON hash or array export, or an Aseprite
+// JSON export, along with the image it references.
+// Aseprite frame tags and TexturePacker animations are both loaded as animations.
+func (a *AssetLoader) LoadAtlas(atlasPath string) (*content.Atlas, error) {
+	atlasBytes, err := a.readFSFile(atlasPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var file atlasFile
+
+	err = json.Unmarshal(atlasBytes, &file)
+	if err != nil {
+		return nil, fmt.Errorf("parsing atlas %v: %w", atlasPath, err)
+	}
+
+	frames, err := decodeAtlasFrames(file.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("parsing atlas frames %v: %w", atlasPath, err)
+	}
+
+	if file.Meta.Image == "" {
+		return nil, fmt.Errorf("atlas %v is missing meta image", atlasPath)
+	}
+
+	img, err := a.LoadImage(path.Join(path.Dir(atlasPath), file.Meta.Image))
+	if err != nil {
+		return nil, fmt.Errorf("loading atlas image %v: %w", atlasPath, err)
+	}
+
+	atlas := &content.Atlas{
+		Image:      img,
+		Sprites:    make(map[string]*content.Sprite, len(frames)),
+		Frames:     make([]content.Frame, len(frames)),
+		Animations: make(map[string]*content.Animation),
+	}
+
+	for i, f := range frames {
+		sprite := f.sprite(img)
+		atlas.Sprites[sprite.Name] = sprite
+		atlas.Frames[i] = content.Frame{
+			Sprite: sprite,
+			// aseprite durations are in milliseconds
+			Duration: f.Duration / 1000,
+		}
+	}
+
+	for _, tag := range file.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("atlas %v tag %v has invalid frame range", atlasPath, tag.Name)
+		}
+
+		atlas.Animations[tag.Name] = &content.Animation{
+			Name:      tag.Name,
+			Direction: atlasDirection(tag.Direction),
+			Frames:    atlas.Frames[tag.From : tag.To+1],
+		}
+	}
+
+	for name, spriteNames := range file.Animations {
+		anim := &content.Animation{
+			Name:      name,
+			Direction: content.AnimationForward,
+			Frames:    make([]content.Frame, len(spriteNames)),
+		}
+
+		for i, spriteName := range spriteNames {
+			sprite, ok := atlas.Sprites[spriteName]
+			if !ok {
+				return nil, fmt.Errorf(
+					"atlas %v animation %v references missing sprite %v",
+					atlasPath, name, spriteName,
+				)
+			}
+
+			anim.Frames[i] = content.Frame{Sprite: sprite}
+		}
+
+		atlas.Animations[name] = anim
+	}
+
+	return atlas, nil
+}
+
+func (f atlasFrame) sprite(img *ebiten.Image) *content.Sprite {
+	w, h := f.Frame.W, f.Frame.H
+	if f.Rotated {
+		w, h = h, w
+	}
+
+	sx, sy := img.Bounds().Min.X+f.Frame.X, img.Bounds().Min.Y+f.Frame.Y
+	sprite := &content.Sprite{
+		Image:   img.SubImage(image.Rect(sx, sy, sx+w, sy+h)).(*ebiten.Image),
+		Page:    img,
+		Name:    f.Filename,
+		Rotated: f.Rotated,
+		Pivot:   f.Pivot,
+	}
+
+	if f.Trimmed {
+		sprite.SourceSize = image.Pt(f.SourceSize.W, f.SourceSize.H)
+		sprite.TrimOffset = image.Pt(f.SpriteSourceSize.X, f.SpriteSourceSize.Y)
+	}
+
+	return sprite
+}
+
+func atlasDirection(direction string) content.AnimationDirection {
+	switch direction {
+	case "reverse":
+		return content.AnimationReverse
+	case "pingpong", "pingpong_reverse":
+		return content.AnimationPingPong
+	default:
+		return content.AnimationForward
+	}
+}
+
+// decodeAtlasFrames reads frames from either an array or a hash, for hashes
+// the key order is kept as aseprite tags reference frames by index.
+func decodeAtlasFrames(raw json.RawMessage) ([]atlasFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("missing frames")
+	}
+
+	if raw[0] == '[' {
+		var frames []atlasFrame
+		err := json.Unmarshal(raw, &frames)
+
+		return frames, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+
+	// opening brace
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var frames []atlasFrame
+
+	for dec.More() {
+		keyToken, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var frame atlasFrame
+
+		err = dec.Decode(&frame)
+		if err != nil {
+			return nil, err
+		}
+
+		frame.Filename, _ = keyToken.(string)
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
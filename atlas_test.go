@@ -0,0 +1,185 @@
+package igloo_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+)
+
+// frames are out of alphabetical order to check hash order is kept
+const (
+	atlasHashFrames = `{
+ "walk-2": {"frame": {"x": 0, "y": 0, "w": 4, "h": 2}, "rotated": true, "duration": 100},
+ "walk-1": {"frame": {"x": 4, "y": 0, "w": 2, "h": 2}, "trimmed": true,
+  "spriteSourceSize": {"x": 1, "y": 2, "w": 2, "h": 2},
+  "sourceSize": {"w": 4, "h": 6}, "duration": 200},
+ "idle": {"frame": {"x": 0, "y": 4, "w": 4, "h": 4}, "duration": 300}
+}`
+	atlasArrayFrames = `[
+ {"filename": "walk-2", "frame": {"x": 0, "y": 0, "w": 4, "h": 2}, "rotated": true,
+  "duration": 100},
+ {"filename": "walk-1", "frame": {"x": 4, "y": 0, "w": 2, "h": 2}, "trimmed": true,
+  "spriteSourceSize": {"x": 1, "y": 2, "w": 2, "h": 2},
+  "sourceSize": {"w": 4, "h": 6}, "duration": 200},
+ {"filename": "idle", "frame": {"x": 0, "y": 4, "w": 4, "h": 4}, "duration": 300}
+]`
+)
+
+func atlasJSON(frames, tags string) []byte {
+	return []byte(`{"frames": ` + frames + `,
+ "meta": {"app": "test", "image": "atlas.png", "frameTags": [` + tags + `]}}`)
+}
+
+func atlasPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 16))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestLoadAtlasFrames(t *testing.T) {
+	tests := map[string]struct {
+		frames string
+	}{
+		"hash":  {frames: atlasHashFrames},
+		"array": {frames: atlasArrayFrames},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"atlas.png":  {Data: atlasPNG(t)},
+				"atlas.json": {Data: atlasJSON(tc.frames, "")},
+			}
+
+			atlas, err := igloo.NewAssetLoader(fsys, ".").LoadAtlas("atlas.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := []string{"walk-2", "walk-1", "idle"}
+			durations := []float64{0.1, 0.2, 0.3}
+
+			if len(atlas.Frames) != len(names) {
+				t.Fatalf("expected: %v frames, got: %v", len(names), len(atlas.Frames))
+			}
+
+			for i, frame := range atlas.Frames {
+				if frame.Sprite.Name != names[i] || frame.Duration != durations[i] {
+					t.Fatalf("expected: %v for %v, got: %+v", names[i], durations[i], frame)
+				}
+
+				if atlas.Sprites[names[i]] != frame.Sprite || frame.Sprite.Page != atlas.Image {
+					t.Fatalf("expected: sprite %v on the atlas page", names[i])
+				}
+			}
+
+			checkAtlasSprites(t, atlas)
+		})
+	}
+}
+
+func checkAtlasSprites(t *testing.T, atlas *content.Atlas) {
+	// rotated frames are stored with their width and height swapped
+	rotated := atlas.Sprites["walk-2"]
+	if bounds := rotated.Image.Bounds(); !rotated.Rotated || bounds != image.Rect(0, 0, 2, 4) {
+		t.Fatalf("expected: rotated sprite in 0 0 2 4, got: %v", bounds)
+	}
+
+	if w, h := rotated.NativeSize(); w != 4 || h != 2 {
+		t.Fatalf("expected: 4x2, got: %vx%v", w, h)
+	}
+
+	trimmed := atlas.Sprites["walk-1"]
+	if trimmed.TrimOffset != image.Pt(1, 2) || trimmed.SourceSize != image.Pt(4, 6) {
+		t.Fatalf("expected: trimmed at 1 2 in 4x6, got: %+v", trimmed)
+	}
+
+	if w, h := trimmed.NativeSize(); w != 4 || h != 6 {
+		t.Fatalf("expected: 4x6, got: %vx%v", w, h)
+	}
+
+	idle := atlas.Sprites["idle"]
+	if idle.TrimOffset != (image.Point{}) || idle.SourceSize != (image.Point{}) {
+		t.Fatalf("expected: untrimmed sprite, got: %+v", idle)
+	}
+}
+
+func TestLoadAtlasTags(t *testing.T) {
+	tests := map[string]struct {
+		tag       string
+		direction content.AnimationDirection
+		frames    []string
+		expectErr bool
+	}{
+		"forward": {
+			tag:       `{"name": "walk", "from": 0, "to": 1, "direction": "forward"}`,
+			direction: content.AnimationForward,
+			frames:    []string{"walk-2", "walk-1"},
+		},
+		"reverse": {
+			tag:       `{"name": "walk", "from": 1, "to": 2, "direction": "reverse"}`,
+			direction: content.AnimationReverse,
+			frames:    []string{"walk-1", "idle"},
+		},
+		"ping pong": {
+			tag:       `{"name": "walk", "from": 2, "to": 2, "direction": "pingpong"}`,
+			direction: content.AnimationPingPong,
+			frames:    []string{"idle"},
+		},
+		"ping pong reverse": {
+			tag:       `{"name": "walk", "from": 0, "to": 2, "direction": "pingpong_reverse"}`,
+			direction: content.AnimationPingPong,
+			frames:    []string{"walk-2", "walk-1", "idle"},
+		},
+		"past the last frame": {
+			tag:       `{"name": "walk", "from": 1, "to": 3, "direction": "forward"}`,
+			expectErr: true,
+		},
+		"backwards range": {
+			tag:       `{"name": "walk", "from": 2, "to": 1, "direction": "forward"}`,
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"atlas.png":  {Data: atlasPNG(t)},
+				"atlas.json": {Data: atlasJSON(atlasHashFrames, tc.tag)},
+			}
+
+			atlas, err := igloo.NewAssetLoader(fsys, ".").LoadAtlas("atlas.json")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			anim := atlas.Animations["walk"]
+			if anim == nil || anim.Direction != tc.direction || len(anim.Frames) != len(tc.frames) {
+				t.Fatalf("expected: %v with %v, got: %+v", tc.direction, tc.frames, anim)
+			}
+
+			for i, frame := range anim.Frames {
+				if frame.Sprite.Name != tc.frames[i] {
+					t.Fatalf("expected: %v, got: %v", tc.frames[i], frame.Sprite.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package audio
+
+import "github.com/miniscruff/igloo/mathf"
+
+// BusName identifies a bus inside of a mixer
+type BusName string
+
+const (
+	// Master bus that every other bus feeds into
+	BusMaster BusName = "master"
+	// Music bus used for background tracks and crossfades
+	BusMusic BusName = "music"
+	// SFX bus used for short sound effects
+	BusSFX BusName = "sfx"
+	// Voice bus used for dialogue, will duck the music bus by default
+	BusVoice BusName = "voice"
+)
+
+// Bus groups players together so they share a volume and mute state.
+// Buses form a tree where the final volume is multiplied by every parent.
+type Bus struct {
+	name   BusName
+	parent *Bus
+	volume float64
+	muted  bool
+	duck   float64
+}
+
+// NewBus creates a bus at full volume, parent can be nil for a root bus.
+func NewBus(name BusName, parent *Bus) *Bus {
+	return &Bus{
+		name:   name,
+		parent: parent,
+		volume: 1,
+		muted:  false,
+		duck:   1,
+	}
+}
+
+func (b *Bus) Name() BusName {
+	return b.name
+}
+
+func (b *Bus) Parent() *Bus {
+	return b.parent
+}
+
+// Volume returns the volume of just this bus in the range of 0 to 1
+func (b *Bus) Volume() float64 {
+	return b.volume
+}
+
+// SetVolume will change our volume, clamped between 0 and 1
+func (b *Bus) SetVolume(volume float64) {
+	b.volume = mathf.Clamp(volume, 0, 1)
+}
+
+func (b *Bus) Muted() bool {
+	return b.muted
+}
+
+func (b *Bus) SetMuted(muted bool) {
+	b.muted = muted
+}
+
+// Ducked returns the current duck multiplier, 1 when not ducked
+func (b *Bus) Ducked() float64 {
+	return b.duck
+}
+
+// EffectiveVolume is the volume after applying mute, ducking and every parent bus
+func (b *Bus) EffectiveVolume() float64 {
+	if b.muted {
+		return 0
+	}
+
+	volume := b.volume * b.duck
+	if b.parent != nil {
+		volume *= b.parent.EffectiveVolume()
+	}
+
+	return volume
+}
+
+// isUnder returns true if we are the named bus or one of our parents is
+func (b *Bus) isUnder(name BusName) bool {
+	for bus := b; bus != nil; bus = bus.parent {
+		if bus.name == name {
+			return true
+		}
+	}
+
+	return false
+}
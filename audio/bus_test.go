@@ -0,0 +1,63 @@
+package audio_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/miniscruff/igloo/audio"
+)
+
+func TestBusEffectiveVolume(t *testing.T) {
+	tests := map[string]struct {
+		masterVolume float64
+		masterMuted  bool
+		volume       float64
+		muted        bool
+		expected     float64
+	}{
+		"full": {
+			masterVolume: 1,
+			volume:       1,
+			expected:     1,
+		},
+		"multiplies parent": {
+			masterVolume: 0.5,
+			volume:       0.5,
+			expected:     0.25,
+		},
+		"muted": {
+			masterVolume: 1,
+			volume:       1,
+			muted:        true,
+			expected:     0,
+		},
+		"parent muted": {
+			masterVolume: 1,
+			masterMuted:  true,
+			volume:       1,
+			expected:     0,
+		},
+		"clamped": {
+			masterVolume: 2,
+			volume:       -1,
+			expected:     0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			master := audio.NewBus(audio.BusMaster, nil)
+			master.SetVolume(tc.masterVolume)
+			master.SetMuted(tc.masterMuted)
+
+			bus := audio.NewBus(audio.BusMusic, master)
+			bus.SetVolume(tc.volume)
+			bus.SetMuted(tc.muted)
+
+			got := bus.EffectiveVolume()
+			if math.Abs(tc.expected-got) > 0.005 {
+				t.Fatalf("expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
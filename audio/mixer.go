@@ -0,0 +1,317 @@
+package audio
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/audio"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// DuckRule lowers the volume of a target bus while any player
+// on the trigger bus is playing.
+type DuckRule struct {
+	// Bus that causes the ducking when playing
+	Trigger BusName
+	// Bus that will be ducked
+	Target BusName
+	// Volume multiplier of the target while ducked
+	Level float64
+	// Seconds to fade down to the duck level
+	Attack float64
+	// Seconds to fade back up after the trigger stops
+	Release float64
+}
+
+type channel struct {
+	bus    *Bus
+	player *audio.Player
+	gain   float64
+	// gain at the start of the current music fade
+	fadeFrom float64
+}
+
+// Mixer routes players through named buses, handles music crossfades and
+// ducking. It is ticked by the scene ticker so fades line up with game time.
+type Mixer struct {
+	context   *audio.Context
+	buses     map[BusName]*Bus
+	channels  []*channel
+	music     *channel
+	fadingOut []*channel
+	musicFade *mathf.Tween
+	ducks     []DuckRule
+	isPaused  bool
+}
+
+// NewMixer creates a mixer with the master, music, sfx and voice buses
+// where voice ducks music.
+func NewMixer(context *audio.Context) *Mixer {
+	master := NewBus(BusMaster, nil)
+	m := &Mixer{
+		context: context,
+		buses: map[BusName]*Bus{
+			BusMaster: master,
+			BusMusic:  NewBus(BusMusic, master),
+			BusSFX:    NewBus(BusSFX, master),
+			BusVoice:  NewBus(BusVoice, master),
+		},
+		isPaused: false,
+	}
+
+	m.AddDuckRule(DuckRule{
+		Trigger: BusVoice,
+		Target:  BusMusic,
+		Level:   0.35,
+		Attack:  0.15,
+		Release: 0.6,
+	})
+
+	return m
+}
+
+func (m *Mixer) Context() *audio.Context {
+	return m.context
+}
+
+// Bus returns the named bus or nil if it does not exist
+func (m *Mixer) Bus(name BusName) *Bus {
+	return m.buses[name]
+}
+
+// AddBus creates a new bus under parent, or returns the existing bus.
+// An unknown parent will default to the master bus.
+func (m *Mixer) AddBus(name, parent BusName) *Bus {
+	if bus, ok := m.buses[name]; ok {
+		return bus
+	}
+
+	parentBus, ok := m.buses[parent]
+	if !ok {
+		parentBus = m.buses[BusMaster]
+	}
+
+	bus := NewBus(name, parentBus)
+	m.buses[name] = bus
+
+	return bus
+}
+
+// AddDuckRule will start ducking the target bus while the trigger bus plays
+func (m *Mixer) AddDuckRule(rule DuckRule) {
+	m.ducks = append(m.ducks, rule)
+}
+
+// ClearDuckRules removes all ducking, including the default voice rule
+func (m *Mixer) ClearDuckRules() {
+	m.ducks = nil
+
+	for _, bus := range m.buses {
+		bus.duck = 1
+	}
+}
+
+func (m *Mixer) busOrMaster(name BusName) *Bus {
+	bus, ok := m.buses[name]
+	if !ok {
+		return m.buses[BusMaster]
+	}
+
+	return bus
+}
+
+// Play starts a player routed through the bus, the mixer will release the
+// player once it is no longer playing.
+func (m *Mixer) Play(bus BusName, player *audio.Player) {
+	ch := &channel{
+		bus:    m.busOrMaster(bus),
+		player: player,
+		gain:   1,
+	}
+	m.channels = append(m.channels, ch)
+
+	player.SetVolume(ch.bus.EffectiveVolume())
+	player.Play()
+}
+
+// Music returns the currently playing music player, if any
+func (m *Mixer) Music() *audio.Player {
+	if m.music == nil {
+		return nil
+	}
+
+	return m.music.player
+}
+
+// PlayMusic crossfades from the current music track to player over
+// duration seconds. A duration of zero swaps immediately.
+// Starting a new fade replaces any fade still in progress, tracks that were
+// fading out continue to fade out from their current volume.
+func (m *Mixer) PlayMusic(player *audio.Player, duration float64) {
+	if m.music != nil && m.music.player == player {
+		return
+	}
+
+	// a track fading out fades back in from where it is
+	incoming := m.takeFadingOut(player)
+	if incoming == nil {
+		incoming = &channel{
+			bus:    m.buses[BusMusic],
+			player: player,
+			gain:   0,
+		}
+		m.channels = append(m.channels, incoming)
+
+		player.SetVolume(0)
+		player.Play()
+	}
+
+	if m.music != nil {
+		m.fadingOut = append(m.fadingOut, m.music)
+	}
+
+	m.music = incoming
+	m.fadeMusic(duration)
+}
+
+// StopMusic fades out the current music over duration seconds
+func (m *Mixer) StopMusic(duration float64) {
+	if m.music == nil {
+		return
+	}
+
+	m.fadingOut = append(m.fadingOut, m.music)
+	m.music = nil
+	m.fadeMusic(duration)
+}
+
+// takeFadingOut removes and returns the fading out channel of player, if any
+func (m *Mixer) takeFadingOut(player *audio.Player) *channel {
+	for i, ch := range m.fadingOut {
+		if ch.player == player {
+			m.fadingOut = append(m.fadingOut[:i], m.fadingOut[i+1:]...)
+			return ch
+		}
+	}
+
+	return nil
+}
+
+// fadeMusic replaces our music fade with one fading our music in and
+// everything fading out down to silence, starting from their current gains.
+func (m *Mixer) fadeMusic(duration float64) {
+	incoming := m.music
+	if incoming != nil {
+		incoming.fadeFrom = incoming.gain
+	}
+
+	for _, ch := range m.fadingOut {
+		ch.fadeFrom = ch.gain
+	}
+
+	update := func(value float64) {
+		if incoming != nil {
+			incoming.gain = mathf.Lerp(incoming.fadeFrom, 1, value)
+		}
+
+		for _, ch := range m.fadingOut {
+			ch.gain = mathf.Lerp(ch.fadeFrom, 0, value)
+		}
+	}
+
+	done := func() {
+		for _, ch := range m.fadingOut {
+			if m.music == nil || ch.player != m.music.player {
+				ch.player.Pause()
+			}
+		}
+
+		m.fadingOut = m.fadingOut[:0]
+		m.musicFade = nil
+	}
+
+	if duration <= 0 {
+		update(1)
+		done()
+		m.apply()
+
+		return
+	}
+
+	m.musicFade = mathf.NewTween(
+		duration,
+		mathf.TweenUpdateFunc(update),
+		mathf.TweenOnComplete(func(t *mathf.Tween) {
+			done()
+		}),
+		mathf.TweenPlay(),
+	)
+}
+
+func (m *Mixer) Pause() {
+	m.isPaused = true
+}
+
+func (m *Mixer) Resume() {
+	m.isPaused = false
+}
+
+func (m *Mixer) IsPaused() bool {
+	return m.isPaused
+}
+
+// Tick advances fades and ducking then applies bus volumes to every player
+func (m *Mixer) Tick() {
+	if m.musicFade != nil {
+		m.musicFade.Tick()
+	}
+
+	m.tickDucks()
+	m.apply()
+}
+
+func (m *Mixer) tickDucks() {
+	delta := mathf.TickDelta()
+
+	for _, rule := range m.ducks {
+		target, ok := m.buses[rule.Target]
+		if !ok {
+			continue
+		}
+
+		goal, fade := 1.0, rule.Release
+		if m.isBusPlaying(rule.Trigger) {
+			goal, fade = rule.Level, rule.Attack
+		}
+
+		if fade <= 0 {
+			target.duck = goal
+			continue
+		}
+
+		target.duck = mathf.MoveTowards(target.duck, goal, (1-rule.Level)/fade*delta)
+	}
+}
+
+func (m *Mixer) isBusPlaying(name BusName) bool {
+	for _, ch := range m.channels {
+		if ch.player.IsPlaying() && ch.bus.isUnder(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Mixer) apply() {
+	activeChannels := m.channels[:0]
+
+	for _, ch := range m.channels {
+		if !ch.player.IsPlaying() && ch != m.music {
+			continue
+		}
+
+		ch.player.SetVolume(ch.bus.EffectiveVolume() * ch.gain)
+		activeChannels = append(activeChannels, ch)
+	}
+
+	m.channels = activeChannels
+}
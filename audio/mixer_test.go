@@ -0,0 +1,147 @@
+package audio_test
+
+import (
+	"math"
+	"testing"
+
+	ebaudio "github.com/hajimehoshi/ebiten/v2/audio"
+
+	"github.com/miniscruff/igloo/audio"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// only one audio context can exist at a time
+var testContext = ebaudio.NewContext(48000)
+
+func newTestPlayer() *ebaudio.Player {
+	// ten seconds of silence
+	return testContext.NewPlayerFromBytes(make([]byte, 48000*4*10))
+}
+
+// tickFor ticks a mixer for at least seconds
+func tickFor(m *audio.Mixer, seconds float64) {
+	ticks := int(seconds/mathf.TickDelta()) + 2
+	for i := 0; i < ticks; i++ {
+		m.Tick()
+	}
+}
+
+func TestMixerMusic(t *testing.T) {
+	tests := map[string]struct {
+		run func(m *audio.Mixer, players []*ebaudio.Player)
+		// index of the expected music player, -1 for none
+		music   int
+		playing []bool
+	}{
+		"swap immediately": {
+			run: func(m *audio.Mixer, players []*ebaudio.Player) {
+				m.PlayMusic(players[0], 0)
+				m.PlayMusic(players[1], 0)
+			},
+			music:   1,
+			playing: []bool{false, true, false},
+		},
+		"crossfade": {
+			run: func(m *audio.Mixer, players []*ebaudio.Player) {
+				m.PlayMusic(players[0], 0)
+				m.PlayMusic(players[1], 0.5)
+				tickFor(m, 0.5)
+			},
+			music:   1,
+			playing: []bool{false, true, false},
+		},
+		"stop music": {
+			run: func(m *audio.Mixer, players []*ebaudio.Player) {
+				m.PlayMusic(players[0], 0)
+				m.StopMusic(0.5)
+				tickFor(m, 0.5)
+			},
+			music:   -1,
+			playing: []bool{false, false, false},
+		},
+		"play again while stopping": {
+			run: func(m *audio.Mixer, players []*ebaudio.Player) {
+				m.PlayMusic(players[0], 0)
+				m.StopMusic(0.5)
+				tickFor(m, 0.2)
+				m.PlayMusic(players[0], 0.5)
+				tickFor(m, 0.5)
+			},
+			music:   0,
+			playing: []bool{true, false, false},
+		},
+		"overlapping crossfades": {
+			run: func(m *audio.Mixer, players []*ebaudio.Player) {
+				m.PlayMusic(players[0], 0)
+				m.PlayMusic(players[1], 0.5)
+				tickFor(m, 0.2)
+				m.PlayMusic(players[2], 0.5)
+				tickFor(m, 0.5)
+			},
+			music:   2,
+			playing: []bool{false, false, true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := audio.NewMixer(testContext)
+			players := []*ebaudio.Player{newTestPlayer(), newTestPlayer(), newTestPlayer()}
+
+			defer func() {
+				for _, p := range players {
+					p.Close()
+				}
+			}()
+
+			tc.run(m, players)
+
+			var expectedMusic *ebaudio.Player
+			if tc.music >= 0 {
+				expectedMusic = players[tc.music]
+			}
+
+			if m.Music() != expectedMusic {
+				t.Fatalf("expected: music %v, got: %v", expectedMusic, m.Music())
+			}
+
+			for i, p := range players {
+				if p.IsPlaying() != tc.playing[i] {
+					t.Fatalf("expected: player %v playing %v, got: %v", i, tc.playing[i], p.IsPlaying())
+				}
+			}
+
+			if expectedMusic != nil && math.Abs(expectedMusic.Volume()-1) > 0.001 {
+				t.Fatalf("expected: full music volume, got: %v", expectedMusic.Volume())
+			}
+		})
+	}
+}
+
+func TestMixerDucking(t *testing.T) {
+	m := audio.NewMixer(testContext)
+	music := newTestPlayer()
+	voice := newTestPlayer()
+
+	defer music.Close()
+	defer voice.Close()
+
+	m.PlayMusic(music, 0)
+	m.Play(audio.BusVoice, voice)
+	tickFor(m, 0.15)
+
+	if ducked := m.Bus(audio.BusMusic).Ducked(); math.Abs(ducked-0.35) > 0.001 {
+		t.Fatalf("expected: 0.35, got: %v", ducked)
+	}
+
+	if math.Abs(music.Volume()-0.35) > 0.001 {
+		t.Fatalf("expected: 0.35, got: %v", music.Volume())
+	}
+
+	voice.Pause()
+	tickFor(m, 0.6)
+
+	if ducked := m.Bus(audio.BusMusic).Ducked(); ducked != 1 {
+		t.Fatalf("expected: 1, got: %v", ducked)
+	}
+}
@@ -0,0 +1,79 @@
+// igloo-pack packs an assets directory into a single compressed archive
+// that can be used as the GameConfig file system.
+//
+//	igloo-pack -o assets.igloo ./assets
+package main
+
+import (
+	"compress/flate"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/miniscruff/igloo/assetfs"
+)
+
+func main() {
+	output := flag.String("o", "assets.igloo", "archive file to write")
+	level := flag.Int("level", flate.BestCompression, "flate compression level, -2 to 9")
+	modTimes := flag.Bool("modtimes", false, "keep modification times, archives differ per checkout")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: igloo-pack [flags] <assets dir>\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var options []assetfs.ArchiveOption
+	if *modTimes {
+		options = append(options, assetfs.ArchiveWithModTimes())
+	}
+
+	err := pack(flag.Arg(0), *output, *level, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "igloo-pack: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func pack(dir, output string, level int, options []assetfs.ArchiveOption) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+
+	err = assetfs.WriteArchive(file, os.DirFS(dir), level, options...)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	// verify every file reads back before reporting success
+	archive, err := assetfs.OpenArchiveFile(output)
+	if err != nil {
+		return err
+	}
+
+	defer archive.Close()
+
+	for _, name := range archive.Paths() {
+		if _, err := archive.ReadFile(name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("packed %v files into %v\n", len(archive.Paths()), output)
+
+	return nil
+}
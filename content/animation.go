@@ -0,0 +1,24 @@
+package content
+
+// AnimationDirection is the order frames of an animation should play in
+type AnimationDirection string
+
+const (
+	AnimationForward  AnimationDirection = "forward"
+	AnimationReverse  AnimationDirection = "reverse"
+	AnimationPingPong AnimationDirection = "pingpong"
+)
+
+// Frame is a single sprite of an animation
+type Frame struct {
+	Sprite *Sprite
+	// Duration of the frame in seconds, zero if the source did not include one
+	Duration float64
+}
+
+// Animation is a named and ordered list of frames
+type Animation struct {
+	Name      string
+	Direction AnimationDirection
+	Frames    []Frame
+}
@@ -0,0 +1,21 @@
+package content
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Atlas is a texture containing many named sprites
+type Atlas struct {
+	Image      *ebiten.Image
+	Sprites    map[string]*Sprite
+	Frames     []Frame
+	Animations map[string]*Animation
+}
+
+// Sprite returns the named sprite or nil if it does not exist
+func (a *Atlas) Sprite(name string) *Sprite {
+	return a.Sprites[name]
+}
+
+// Animation returns the named animation or nil if it does not exist
+func (a *Atlas) Animation(name string) *Animation {
+	return a.Animations[name]
+}
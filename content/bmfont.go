@@ -0,0 +1,328 @@
+package content
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// BitmapChar describes a single glyph inside of a bitmap font page
+type BitmapChar struct {
+	ID       rune `xml:"id,attr"`
+	X        int  `xml:"x,attr"`
+	Y        int  `xml:"y,attr"`
+	Width    int  `xml:"width,attr"`
+	Height   int  `xml:"height,attr"`
+	XOffset  int  `xml:"xoffset,attr"`
+	YOffset  int  `xml:"yoffset,attr"`
+	XAdvance int  `xml:"xadvance,attr"`
+	Page     int  `xml:"page,attr"`
+}
+
+// BitmapKerning adjusts the advance between two characters
+type BitmapKerning struct {
+	First  rune `xml:"first,attr"`
+	Second rune `xml:"second,attr"`
+	Amount int  `xml:"amount,attr"`
+}
+
+// BitmapPage is a texture file referenced by a bitmap font
+type BitmapPage struct {
+	ID   int    `xml:"id,attr"`
+	File string `xml:"file,attr"`
+}
+
+// BitmapFontDef is the parsed contents of an AngelCode BMFont file
+type BitmapFontDef struct {
+	Face       string
+	Size       int
+	LineHeight int
+	Base       int
+	Pages      []BitmapPage
+	Chars      []BitmapChar
+	Kernings   []BitmapKerning
+}
+
+type bitmapFontXML struct {
+	Info struct {
+		Face string `xml:"face,attr"`
+		Size int    `xml:"size,attr"`
+	} `xml:"info"`
+	Common struct {
+		LineHeight int `xml:"lineHeight,attr"`
+		Base       int `xml:"base,attr"`
+	} `xml:"common"`
+	Pages    []BitmapPage    `xml:"pages>page"`
+	Chars    []BitmapChar    `xml:"chars>char"`
+	Kernings []BitmapKerning `xml:"kernings>kerning"`
+}
+
+// ParseBitmapFont parses a BMFont descriptor in either the text or XML format
+func ParseBitmapFont(data []byte) (*BitmapFontDef, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '<' {
+		return parseBitmapFontXML(trimmed)
+	}
+
+	return parseBitmapFontText(trimmed)
+}
+
+func parseBitmapFontXML(data []byte) (*BitmapFontDef, error) {
+	var file bitmapFontXML
+
+	err := xml.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("parsing bitmap font xml: %w", err)
+	}
+
+	return &BitmapFontDef{
+		Face:       file.Info.Face,
+		Size:       file.Info.Size,
+		LineHeight: file.Common.LineHeight,
+		Base:       file.Common.Base,
+		Pages:      file.Pages,
+		Chars:      file.Chars,
+		Kernings:   file.Kernings,
+	}, nil
+}
+
+func parseBitmapFontText(data []byte) (*BitmapFontDef, error) {
+	def := &BitmapFontDef{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		tag, attrs := splitBitmapFontLine(scanner.Text())
+		line := bitmapFontLine{attrs: attrs}
+
+		switch tag {
+		case "info":
+			def.Face = attrs["face"]
+			def.Size = line.int("size")
+		case "common":
+			def.LineHeight = line.int("lineHeight")
+			def.Base = line.int("base")
+		case "page":
+			def.Pages = append(def.Pages, BitmapPage{
+				ID:   line.int("id"),
+				File: attrs["file"],
+			})
+		case "char":
+			def.Chars = append(def.Chars, BitmapChar{
+				ID:       rune(line.int("id")),
+				X:        line.int("x"),
+				Y:        line.int("y"),
+				Width:    line.int("width"),
+				Height:   line.int("height"),
+				XOffset:  line.int("xoffset"),
+				YOffset:  line.int("yoffset"),
+				XAdvance: line.int("xadvance"),
+				Page:     line.int("page"),
+			})
+		case "kerning":
+			def.Kernings = append(def.Kernings, BitmapKerning{
+				First:  rune(line.int("first")),
+				Second: rune(line.int("second")),
+				Amount: line.int("amount"),
+			})
+		}
+
+		if line.err != nil {
+			return nil, fmt.Errorf("parsing bitmap font line %v: %w", lineNum, line.err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading bitmap font: %w", err)
+	}
+
+	if def.LineHeight == 0 {
+		return nil, fmt.Errorf("parsing bitmap font: missing common line height")
+	}
+
+	return def, nil
+}
+
+type bitmapFontLine struct {
+	attrs map[string]string
+	err   error
+}
+
+// int returns the attribute as an int, missing attributes are zero and
+// the first parsing error is kept.
+func (l *bitmapFontLine) int(key string) int {
+	value, ok := l.attrs[key]
+	if !ok || l.err != nil {
+		return 0
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		l.err = fmt.Errorf("attribute %v: %w", key, err)
+	}
+
+	return i
+}
+
+// splitBitmapFontLine splits `tag key=value key="quoted value"` into
+// the tag and a map of attributes.
+func splitBitmapFontLine(line string) (string, map[string]string) {
+	line = strings.TrimSpace(line)
+	attrs := make(map[string]string)
+
+	tagEnd := strings.IndexAny(line, " \t")
+	if tagEnd < 0 {
+		return line, attrs
+	}
+
+	tag := line[:tagEnd]
+	rest := line[tagEnd:]
+
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+
+		attrs[key] = value
+	}
+
+	return tag, attrs
+}
+
+// BitmapFace is a font.Face drawing glyphs out of bitmap font pages
+// so it can be used anywhere an opentype face can.
+type BitmapFace struct {
+	def      *BitmapFontDef
+	pages    []image.Image
+	chars    map[rune]BitmapChar
+	kernings map[[2]rune]int
+}
+
+// NewBitmapFace creates a face from a parsed definition and its page images
+// in page id order.
+func NewBitmapFace(def *BitmapFontDef, pages []image.Image) (*BitmapFace, error) {
+	face := &BitmapFace{
+		def:      def,
+		pages:    pages,
+		chars:    make(map[rune]BitmapChar, len(def.Chars)),
+		kernings: make(map[[2]rune]int, len(def.Kernings)),
+	}
+
+	for _, c := range def.Chars {
+		if c.Page < 0 || c.Page >= len(pages) {
+			return nil, fmt.Errorf("bitmap font char %v references missing page %v", c.ID, c.Page)
+		}
+
+		face.chars[c.ID] = c
+	}
+
+	for _, k := range def.Kernings {
+		face.kernings[[2]rune{k.First, k.Second}] = k.Amount
+	}
+
+	return face, nil
+}
+
+func (f *BitmapFace) Def() *BitmapFontDef {
+	return f.def
+}
+
+func (f *BitmapFace) Close() error {
+	return nil
+}
+
+func (f *BitmapFace) Glyph(dot fixed.Point26_6, r rune) (
+	dr image.Rectangle,
+	mask image.Image,
+	maskp image.Point,
+	advance fixed.Int26_6,
+	ok bool,
+) {
+	c, ok := f.chars[r]
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+
+	x := dot.X.Round() + c.XOffset
+	y := dot.Y.Round() - f.def.Base + c.YOffset
+	dr = image.Rect(x, y, x+c.Width, y+c.Height)
+	page := f.pages[c.Page]
+	maskp = page.Bounds().Min.Add(image.Pt(c.X, c.Y))
+
+	return dr, page, maskp, fixed.I(c.XAdvance), true
+}
+
+func (f *BitmapFace) GlyphBounds(r rune) (
+	bounds fixed.Rectangle26_6,
+	advance fixed.Int26_6,
+	ok bool,
+) {
+	c, ok := f.chars[r]
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+
+	minX, minY := c.XOffset, c.YOffset-f.def.Base
+	bounds = fixed.R(minX, minY, minX+c.Width, minY+c.Height)
+
+	return bounds, fixed.I(c.XAdvance), true
+}
+
+func (f *BitmapFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	c, ok := f.chars[r]
+	if !ok {
+		return 0, false
+	}
+
+	return fixed.I(c.XAdvance), true
+}
+
+func (f *BitmapFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return fixed.I(f.kernings[[2]rune{r0, r1}])
+}
+
+func (f *BitmapFace) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:    fixed.I(f.def.LineHeight),
+		Ascent:    fixed.I(f.def.Base),
+		Descent:   fixed.I(f.def.LineHeight - f.def.Base),
+		CapHeight: fixed.I(f.def.Base),
+		XHeight:   fixed.I(f.def.Base / 2),
+	}
+}
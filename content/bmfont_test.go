@@ -0,0 +1,111 @@
+package content_test
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/miniscruff/igloo/content"
+)
+
+const bitmapFontText = `info face="Pixel Font" size=16 bold=0
+common lineHeight=18 base=14 scaleW=64 scaleH=64 pages=1
+page id=0 file="pixel font.png"
+chars count=2
+char id=65 x=1 y=2 width=7 height=9 xoffset=0 yoffset=5 xadvance=8 page=0 chnl=15
+char id=86 x=9 y=2 width=7 height=9 xoffset=1 yoffset=5 xadvance=8 page=0 chnl=15
+kernings count=1
+kerning first=65 second=86 amount=-1
+`
+
+const bitmapFontXML = `<?xml version="1.0"?>
+<font>
+  <info face="Pixel Font" size="16"/>
+  <common lineHeight="18" base="14" scaleW="64" scaleH="64" pages="1"/>
+  <pages>
+    <page id="0" file="pixel font.png"/>
+  </pages>
+  <chars count="2">
+    <char id="65" x="1" y="2" width="7" height="9" xoffset="0" yoffset="5" xadvance="8" page="0"/>
+    <char id="86" x="9" y="2" width="7" height="9" xoffset="1" yoffset="5" xadvance="8" page="0"/>
+  </chars>
+  <kernings count="1">
+    <kerning first="65" second="86" amount="-1"/>
+  </kernings>
+</font>`
+
+func TestParseBitmapFont(t *testing.T) {
+	tests := map[string]string{
+		"text": bitmapFontText,
+		"xml":  bitmapFontXML,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			def, err := content.ParseBitmapFont([]byte(data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if def.Face != "Pixel Font" || def.Size != 16 {
+				t.Fatalf("expected info Pixel Font 16, got: %v %v", def.Face, def.Size)
+			}
+
+			if def.LineHeight != 18 || def.Base != 14 {
+				t.Fatalf("expected common 18 14, got: %v %v", def.LineHeight, def.Base)
+			}
+
+			if len(def.Pages) != 1 || def.Pages[0].File != "pixel font.png" {
+				t.Fatalf("expected one page, got: %v", def.Pages)
+			}
+
+			if len(def.Chars) != 2 || def.Chars[1].ID != 'V' || def.Chars[1].XOffset != 1 {
+				t.Fatalf("expected two chars, got: %v", def.Chars)
+			}
+
+			if len(def.Kernings) != 1 || def.Kernings[0].Amount != -1 {
+				t.Fatalf("expected one kerning, got: %v", def.Kernings)
+			}
+		})
+	}
+}
+
+func TestBitmapFaceGlyph(t *testing.T) {
+	def, err := content.ParseBitmapFont([]byte(bitmapFontText))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page := image.NewAlpha(image.Rect(0, 0, 64, 64))
+
+	face, err := content.NewBitmapFace(def, []image.Image{page})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dr, _, maskp, advance, ok := face.Glyph(fixed.P(10, 20), 'A')
+	if !ok {
+		t.Fatal("expected glyph for A")
+	}
+
+	if dr != image.Rect(10, 11, 17, 20) {
+		t.Fatalf("expected dest rect: %v, got: %v", image.Rect(10, 11, 17, 20), dr)
+	}
+
+	if maskp != image.Pt(1, 2) {
+		t.Fatalf("expected mask point: %v, got: %v", image.Pt(1, 2), maskp)
+	}
+
+	if advance != fixed.I(8) {
+		t.Fatalf("expected advance: 8, got: %v", advance)
+	}
+
+	if kern := face.Kern('A', 'V'); kern != fixed.I(-1) {
+		t.Fatalf("expected kern: -1, got: %v", kern)
+	}
+
+	if _, _, _, _, ok := face.Glyph(fixed.P(0, 0), 'Z'); ok {
+		t.Fatal("expected missing glyph for Z")
+	}
+}
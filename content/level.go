@@ -0,0 +1,142 @@
+package content
+
+import (
+	"image"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// IntGridLayer is a grid of integer values, usually used for collision
+// or to mark areas of a level. Zero is an empty cell.
+type IntGridLayer struct {
+	Name     string
+	Width    int
+	Height   int
+	GridSize int
+	OffsetX  float64
+	OffsetY  float64
+	Values   []int
+}
+
+// At returns the value at the grid position or zero if out of range
+func (l *IntGridLayer) At(x, y int) int {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+
+	return l.Values[y*l.Width+x]
+}
+
+// EntityRef references an entity by IID that may be in another level or world
+type EntityRef struct {
+	EntityIID string
+	LayerIID  string
+	LevelIID  string
+	WorldIID  string
+}
+
+// Entity is an instance placed in a level to spawn gameplay objects from
+type Entity struct {
+	Name string
+	IID  string
+	// X and Y is the pivot position in pixels relative to the level
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+	Pivot  mathf.Vec2
+	Grid   image.Point
+	Tags   []string
+	// Sprite is the tile the entity is displayed with in the editor, if any
+	Sprite *Sprite
+	Fields Properties
+}
+
+// EntityLayer is a group of entities
+type EntityLayer struct {
+	Name     string
+	OffsetX  float64
+	OffsetY  float64
+	Visible  bool
+	Entities []*Entity
+}
+
+// LevelInfo describes a level of a world without its layers
+type LevelInfo struct {
+	Name   string
+	IID    string
+	WorldX int
+	WorldY int
+	Width  int
+	Height int
+}
+
+// World is a set of levels laid out in world space
+type World struct {
+	Name   string
+	IID    string
+	Layout string
+	Levels []LevelInfo
+}
+
+// Level is a single loaded level.
+// Layers are ordered from the bottom to the top.
+type Level struct {
+	LevelInfo
+
+	TileLayers    []*TileLayer
+	IntGridLayers []*IntGridLayer
+	EntityLayers  []*EntityLayer
+	Fields        Properties
+}
+
+// TileLayersNamed returns every tile layer with name, a single editor layer
+// is split into multiple tile layers when tiles are stacked.
+func (l *Level) TileLayersNamed(name string) []*TileLayer {
+	var layers []*TileLayer
+
+	for _, layer := range l.TileLayers {
+		if layer.Name == name {
+			layers = append(layers, layer)
+		}
+	}
+
+	return layers
+}
+
+// IntGridLayer returns the named int grid layer or nil
+func (l *Level) IntGridLayer(name string) *IntGridLayer {
+	for _, layer := range l.IntGridLayers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+
+	return nil
+}
+
+// EntityLayer returns the named entity layer or nil
+func (l *Level) EntityLayer(name string) *EntityLayer {
+	for _, layer := range l.EntityLayers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+
+	return nil
+}
+
+// Entities returns every entity with name from all entity layers
+func (l *Level) Entities(name string) []*Entity {
+	var entities []*Entity
+
+	for _, layer := range l.EntityLayers {
+		for _, entity := range layer.Entities {
+			if entity.Name == name {
+				entities = append(entities, entity)
+			}
+		}
+	}
+
+	return entities
+}
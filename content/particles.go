@@ -0,0 +1,198 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// MaxParticles is the most particles a single emitter can draw in one batch
+const MaxParticles = MaxQuads
+
+// EmitterShape is the area new particles are spawned in
+type EmitterShape string
+
+const (
+	// EmitPoint spawns particles at the center of the emitter
+	EmitPoint EmitterShape = "point"
+	// EmitLine spawns particles along a horizontal line of the emitter width
+	EmitLine EmitterShape = "line"
+	// EmitRect spawns particles inside of the emitter width and height
+	EmitRect EmitterShape = "rect"
+	// EmitCircle spawns particles inside of an ellipse filling the emitter
+	EmitCircle EmitterShape = "circle"
+)
+
+// FloatRange is a random value between min and max
+type FloatRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Lerp returns the value at a percent between min and max
+func (r FloatRange) Lerp(percent float64) float64 {
+	return mathf.Lerp(r.Min, r.Max, percent)
+}
+
+// ParticleCurve is a value over the lifetime of a particle
+type ParticleCurve struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// Ease is the name of a mathf ease, see mathf.EaseNamed
+	Ease string `json:"ease"`
+
+	ease mathf.EaseFunc
+}
+
+// At returns the value at a percent of a particles lifetime
+func (c *ParticleCurve) At(percent float64) float64 {
+	if c.ease == nil {
+		c.ease, _ = mathf.EaseNamed(c.Ease)
+		if c.ease == nil {
+			c.ease = mathf.EaseLinear
+		}
+	}
+
+	return mathf.Lerp(c.Start, c.End, c.ease(percent))
+}
+
+// ParticleColorCurve is a color over the lifetime of a particle,
+// colors are red, green, blue and alpha from zero to one.
+type ParticleColorCurve struct {
+	Start [4]float64 `json:"start"`
+	End   [4]float64 `json:"end"`
+	// Ease is the name of a mathf ease, see mathf.EaseNamed
+	Ease string `json:"ease"`
+
+	ease mathf.EaseFunc
+}
+
+// At returns the color at a percent of a particles lifetime
+func (c *ParticleColorCurve) At(percent float64) [4]float64 {
+	if c.ease == nil {
+		c.ease, _ = mathf.EaseNamed(c.Ease)
+		if c.ease == nil {
+			c.ease = mathf.EaseLinear
+		}
+	}
+
+	eased := c.ease(percent)
+
+	var clr [4]float64
+	for i := range clr {
+		clr[i] = mathf.Lerp(c.Start[i], c.End[i], eased)
+	}
+
+	return clr
+}
+
+// ParticleBurst spawns a number of particles at once
+type ParticleBurst struct {
+	// Time in seconds after the emitter starts or loops
+	Time  float64 `json:"time"`
+	Count int     `json:"count"`
+}
+
+// ParticleEmitterDef describes how an emitter spawns and moves particles.
+// Angles are in radians clockwise starting from the right.
+type ParticleEmitterDef struct {
+	// Image is the path of the particle image relative to the definition
+	Image string `json:"image"`
+	// Sprite drawn for each particle, loaded from Image by the asset loader
+	Sprite *Sprite `json:"-"`
+
+	Shape  EmitterShape `json:"shape"`
+	Width  float64      `json:"width"`
+	Height float64      `json:"height"`
+
+	// Rate is how many particles spawn per second
+	Rate   float64         `json:"rate"`
+	Bursts []ParticleBurst `json:"bursts"`
+	// Duration in seconds the emitter spawns for, zero to spawn forever
+	Duration float64 `json:"duration"`
+	// Loop restarts the emitter and its bursts after each duration
+	Loop bool `json:"loop"`
+	// MaxParticles limits how many particles are alive at once,
+	// zero or above the MaxParticles constant uses the constant.
+	MaxParticles int `json:"maxParticles"`
+	// WorldSpace particles stay in place when the emitter moves,
+	// otherwise particles move along with the emitter.
+	WorldSpace bool `json:"worldSpace"`
+
+	// Lifetime in seconds of each particle
+	Lifetime FloatRange `json:"lifetime"`
+	// Speed in pixels per second
+	Speed FloatRange `json:"speed"`
+	// Direction of the starting velocity
+	Direction FloatRange `json:"direction"`
+	// Gravity in pixels per second squared
+	Gravity mathf.Vec2 `json:"gravity"`
+	// Drag slows particles by this percent of their speed every second
+	Drag float64 `json:"drag"`
+
+	// Rotation the particle starts with
+	Rotation FloatRange `json:"rotation"`
+	// AngularVelocity in radians per second
+	AngularVelocity FloatRange `json:"angularVelocity"`
+	// RotationCurve is added to the rotation over the lifetime,
+	// nil only uses the starting rotation and angular velocity.
+	RotationCurve *ParticleCurve `json:"rotationCurve"`
+	// Size scales the sprite over the lifetime, nil keeps the sprite size
+	Size *ParticleCurve `json:"size"`
+	// Color tints the sprite over the lifetime, nil keeps the sprite color
+	Color *ParticleColorCurve `json:"color"`
+}
+
+// Validate checks our values and sorts bursts by time
+func (d *ParticleEmitterDef) Validate() error {
+	switch d.Shape {
+	case "":
+		d.Shape = EmitPoint
+	case EmitPoint, EmitLine, EmitRect, EmitCircle:
+	default:
+		return fmt.Errorf("unknown emitter shape %v", d.Shape)
+	}
+
+	if d.Lifetime.Min <= 0 || d.Lifetime.Min > d.Lifetime.Max {
+		return errors.New("particle lifetime must be positive and max can not be below min")
+	}
+
+	if d.Rate < 0 || d.Duration < 0 || d.MaxParticles < 0 {
+		return errors.New("particle rate, duration and max particles can not be negative")
+	}
+
+	if d.RotationCurve != nil {
+		if _, ok := mathf.EaseNamed(d.RotationCurve.Ease); !ok {
+			return fmt.Errorf("unknown rotation ease %v", d.RotationCurve.Ease)
+		}
+	}
+
+	if d.Size != nil {
+		if _, ok := mathf.EaseNamed(d.Size.Ease); !ok {
+			return fmt.Errorf("unknown size ease %v", d.Size.Ease)
+		}
+	}
+
+	if d.Color != nil {
+		if _, ok := mathf.EaseNamed(d.Color.Ease); !ok {
+			return fmt.Errorf("unknown color ease %v", d.Color.Ease)
+		}
+	}
+
+	sort.SliceStable(d.Bursts, func(i, j int) bool {
+		return d.Bursts[i].Time < d.Bursts[j].Time
+	})
+
+	return nil
+}
+
+// ParticleLimit returns the most particles alive at once
+func (d *ParticleEmitterDef) ParticleLimit() int {
+	if d.MaxParticles <= 0 || d.MaxParticles > MaxParticles {
+		return MaxParticles
+	}
+
+	return d.MaxParticles
+}
@@ -1,10 +1,77 @@
 package content
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// MaxQuads is the most quads a single DrawTriangles call can index,
+// indices are uint16 and each quad uses four vertices.
+const MaxQuads = ebiten.MaxVerticesCount / 4
 
 type Sprite struct {
 	*ebiten.Image
 	ebiten.ColorM
 	ebiten.CompositeMode
 	ebiten.Filter
+
+	// Page is the image our Image was cut from, such as an atlas page or
+	// sprite sheet, nil when Image was not cut from a larger image.
+	Page *ebiten.Image
+	// Name of the sprite when loaded from an atlas
+	Name string
+	// SourceSize is the size of the original image before trimming,
+	// zero if the sprite was never trimmed.
+	SourceSize image.Point
+	// TrimOffset is the position of the trimmed image inside the source size
+	TrimOffset image.Point
+	// Rotated is true when the image is stored rotated 90 degrees clockwise
+	Rotated bool
+	// Pivot is the normalized pivot point as set in the packing tool
+	Pivot mathf.Vec2
+}
+
+// NativeSize returns the size of the sprite before any trimming or rotation
+// was applied by a packing tool.
+func (s *Sprite) NativeSize() (float64, float64) {
+	if s.SourceSize != (image.Point{}) {
+		return float64(s.SourceSize.X), float64(s.SourceSize.Y)
+	}
+
+	pt := s.Image.Bounds().Size()
+	if s.Rotated {
+		return float64(pt.Y), float64(pt.X)
+	}
+
+	return float64(pt.X), float64(pt.Y)
+}
+
+// PageImage returns our Page or our Image when we do not have one.
+// Sub images keep the coordinates of their page, so our Image bounds
+// are where we are on the page.
+func (s *Sprite) PageImage() *ebiten.Image {
+	if s.Page != nil {
+		return s.Page
+	}
+
+	return s.Image
+}
+
+// RegionGeoM returns the transform that places our image inside of its
+// native size, undoing any rotation and restoring trimmed space.
+func (s *Sprite) RegionGeoM() ebiten.GeoM {
+	var geom ebiten.GeoM
+
+	if s.Rotated {
+		geom.Rotate(-math.Pi / 2)
+		geom.Translate(0, float64(s.Image.Bounds().Dx()))
+	}
+
+	geom.Translate(float64(s.TrimOffset.X), float64(s.TrimOffset.Y))
+
+	return geom
 }
@@ -0,0 +1,65 @@
+package content_test
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+)
+
+func TestSpriteRegionGeoM(t *testing.T) {
+	tests := map[string]struct {
+		sprite *content.Sprite
+		// corners of our image and where they should end up
+		corners  [][2]float64
+		expected [][2]float64
+	}{
+		"plain": {
+			sprite:   &content.Sprite{Image: ebiten.NewImage(4, 2)},
+			corners:  [][2]float64{{0, 0}, {4, 2}},
+			expected: [][2]float64{{0, 0}, {4, 2}},
+		},
+		"trimmed": {
+			sprite: &content.Sprite{
+				Image:      ebiten.NewImage(2, 2),
+				SourceSize: image.Pt(4, 6),
+				TrimOffset: image.Pt(1, 2),
+			},
+			corners:  [][2]float64{{0, 0}, {2, 2}},
+			expected: [][2]float64{{1, 2}, {3, 4}},
+		},
+		"rotated": {
+			// stored clockwise so our top left is the top right of the image
+			sprite:   &content.Sprite{Image: ebiten.NewImage(2, 4), Rotated: true},
+			corners:  [][2]float64{{2, 0}, {0, 4}, {0, 0}},
+			expected: [][2]float64{{0, 0}, {4, 2}, {0, 2}},
+		},
+		"rotated and trimmed": {
+			sprite: &content.Sprite{
+				Image:      ebiten.NewImage(2, 4),
+				Rotated:    true,
+				SourceSize: image.Pt(6, 4),
+				TrimOffset: image.Pt(1, 1),
+			},
+			corners:  [][2]float64{{2, 0}, {0, 4}},
+			expected: [][2]float64{{1, 1}, {5, 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			geom := tc.sprite.RegionGeoM()
+
+			for i, corner := range tc.corners {
+				x, y := geom.Apply(corner[0], corner[1])
+				// rotating leaves some floating point error behind
+				if math.Abs(x-tc.expected[i][0]) > 1e-9 || math.Abs(y-tc.expected[i][1]) > 1e-9 {
+					t.Fatalf("expected: %v, got: %v %v", tc.expected[i], x, y)
+				}
+			}
+		})
+	}
+}
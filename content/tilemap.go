@@ -0,0 +1,215 @@
+package content
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// Properties are custom values set in a level editor
+type Properties map[string]any
+
+// String returns the property as a string or an empty string
+func (p Properties) String(name string) string {
+	value, _ := p[name].(string)
+	return value
+}
+
+// Int returns the property as an int or zero
+func (p Properties) Int(name string) int {
+	switch value := p[name].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
+// Float returns the property as a float64 or zero
+func (p Properties) Float(name string) float64 {
+	switch value := p[name].(type) {
+	case int:
+		return float64(value)
+	case float64:
+		return value
+	default:
+		return 0
+	}
+}
+
+// Bool returns the property as a bool or false
+func (p Properties) Bool(name string) bool {
+	value, _ := p[name].(bool)
+	return value
+}
+
+// Tileset is a collection of tile sprites sharing a size
+type Tileset struct {
+	Name       string
+	TileWidth  int
+	TileHeight int
+	Sprites    []*Sprite
+	Properties map[int]Properties
+}
+
+// Tile is a single placed tile of a tile layer
+type Tile struct {
+	Tileset *Tileset
+	// ID of the tile inside of the tileset
+	ID    int
+	FlipH bool
+	FlipV bool
+	// FlipD flips along the diagonal, swapping x and y
+	FlipD bool
+}
+
+// Empty returns true if no tile is placed
+func (t Tile) Empty() bool {
+	return t.Tileset == nil
+}
+
+// Sprite returns the tile sprite or nil if the tile is empty
+func (t Tile) Sprite() *Sprite {
+	if t.Tileset == nil || t.ID < 0 || t.ID >= len(t.Tileset.Sprites) {
+		return nil
+	}
+
+	return t.Tileset.Sprites[t.ID]
+}
+
+// Properties of the tile as set in its tileset
+func (t Tile) Properties() Properties {
+	if t.Tileset == nil {
+		return nil
+	}
+
+	return t.Tileset.Properties[t.ID]
+}
+
+// FlipGeoM returns the transform that flips a tile of width and height
+// while keeping it in the same cell.
+func (t Tile) FlipGeoM(width, height float64) ebiten.GeoM {
+	var geom ebiten.GeoM
+
+	if t.FlipD {
+		geom.SetElement(0, 0, 0)
+		geom.SetElement(0, 1, 1)
+		geom.SetElement(1, 0, 1)
+		geom.SetElement(1, 1, 0)
+
+		width, height = height, width
+	}
+
+	if t.FlipH {
+		geom.Scale(-1, 1)
+		geom.Translate(width, 0)
+	}
+
+	if t.FlipV {
+		geom.Scale(1, -1)
+		geom.Translate(0, height)
+	}
+
+	return geom
+}
+
+// TileLayer is a grid of tiles
+type TileLayer struct {
+	Name       string
+	Width      int
+	Height     int
+	TileWidth  int
+	TileHeight int
+	OffsetX    float64
+	OffsetY    float64
+	Opacity    float64
+	Visible    bool
+	Tiles      []Tile
+	Properties Properties
+}
+
+// At returns the tile at the grid position or an empty tile if out of range
+func (l *TileLayer) At(x, y int) Tile {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return Tile{}
+	}
+
+	return l.Tiles[y*l.Width+x]
+}
+
+// ObjectShape is the shape of a map object
+type ObjectShape string
+
+const (
+	ObjectRectangle ObjectShape = "rectangle"
+	ObjectEllipse   ObjectShape = "ellipse"
+	ObjectPoint     ObjectShape = "point"
+	ObjectPolygon   ObjectShape = "polygon"
+	ObjectPolyline  ObjectShape = "polyline"
+	ObjectTile      ObjectShape = "tile"
+)
+
+// MapObject is a shape or tile placed in an object layer,
+// usually used to spawn entities or define collision.
+type MapObject struct {
+	ID       int
+	Name     string
+	Type     string
+	Shape    ObjectShape
+	X        float64
+	Y        float64
+	Width    float64
+	Height   float64
+	Rotation float64
+	// Points of polygons and polylines relative to X and Y
+	Points     []mathf.Vec2
+	Tile       Tile
+	Visible    bool
+	Properties Properties
+}
+
+// ObjectLayer is a group of objects
+type ObjectLayer struct {
+	Name       string
+	OffsetX    float64
+	OffsetY    float64
+	Visible    bool
+	Objects    []*MapObject
+	Properties Properties
+}
+
+// Tilemap is a grid based map made of tile and object layers
+type Tilemap struct {
+	Width        int
+	Height       int
+	TileWidth    int
+	TileHeight   int
+	Tilesets     []*Tileset
+	TileLayers   []*TileLayer
+	ObjectLayers []*ObjectLayer
+	Properties   Properties
+}
+
+// TileLayer returns the named tile layer or nil
+func (m *Tilemap) TileLayer(name string) *TileLayer {
+	for _, l := range m.TileLayers {
+		if l.Name == name {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// ObjectLayer returns the named object layer or nil
+func (m *Tilemap) ObjectLayer(name string) *ObjectLayer {
+	for _, l := range m.ObjectLayers {
+		if l.Name == name {
+			return l
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package igloo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is an optional interface for data assets to check their
+// values after decoding.
+type Validator interface {
+	Validate() error
+}
+
+type dataAsset struct {
+	value   any
+	modTime time.Time
+	reload  func() error
+}
+
+// LoadData decodes a json or yaml file into T based on the file extension.
+// If T implements Validator it is validated after decoding.
+// Results are cached by path so loading the same path returns the same value,
+// and reloading will update that value in place.
+func LoadData[T any](a *AssetLoader, dataPath string) (*T, error) {
+	if cached, ok := a.data[dataPath]; ok {
+		value, ok := cached.value.(*T)
+		if !ok {
+			return nil, fmt.Errorf("data %v already loaded as %T", dataPath, cached.value)
+		}
+
+		return value, nil
+	}
+
+	value, err := decodeData[T](a, dataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	a.data[dataPath] = &dataAsset{
+		value:   value,
+		modTime: a.modTime(dataPath),
+		reload: func() error {
+			newValue, err := decodeData[T](a, dataPath)
+			if err != nil {
+				return err
+			}
+
+			*value = *newValue
+
+			return nil
+		},
+	}
+
+	return value, nil
+}
+
+func decodeData[T any](a *AssetLoader, dataPath string) (*T, error) {
+	dataBytes, err := a.readFSFile(dataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	value := new(T)
+
+	switch strings.ToLower(path.Ext(dataPath)) {
+	case ".json":
+		err = json.Unmarshal(dataBytes, value)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(dataBytes, value)
+	default:
+		return nil, fmt.Errorf("unknown data format %v", dataPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decoding data %v: %w", dataPath, err)
+	}
+
+	if validator, ok := any(value).(Validator); ok {
+		err = validator.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("validating data %v: %w", dataPath, err)
+		}
+	}
+
+	return value, nil
+}
+
+// modTime returns the modification time of an asset or zero if unknown
+func (a *AssetLoader) modTime(assetPath string) time.Time {
+	fullPath, err := a.fullPath(assetPath)
+	if err != nil {
+		return time.Time{}
+	}
+
+	info, err := fs.Stat(a.fsys, fullPath)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// Reload decodes a loaded asset again, updating the cached value in place.
+// On error the previous value is kept.
+func (a *AssetLoader) Reload(assetPath string) error {
+	asset, ok := a.data[assetPath]
+	if !ok {
+		return fmt.Errorf("reloading %v: asset not loaded", assetPath)
+	}
+
+	err := asset.reload()
+	if err != nil {
+		return fmt.Errorf("reloading %v: %w", assetPath, err)
+	}
+
+	asset.modTime = a.modTime(assetPath)
+	a.reloaded.Publish(assetPath)
+
+	return nil
+}
+
+// ReloadChanged reloads every loaded asset whose modification time changed,
+// call it periodically while developing for hot reloading.
+// Returns the paths that were reloaded.
+func (a *AssetLoader) ReloadChanged() ([]string, error) {
+	var reloaded []string
+
+	for assetPath, asset := range a.data {
+		modTime := a.modTime(assetPath)
+		if modTime.IsZero() || modTime.Equal(asset.modTime) {
+			continue
+		}
+
+		err := a.Reload(assetPath)
+		if err != nil {
+			return reloaded, err
+		}
+
+		reloaded = append(reloaded, assetPath)
+	}
+
+	return reloaded, nil
+}
+
+// OnReload subscribes to assets being reloaded, the handler receives the asset path
+func (a *AssetLoader) OnReload(fn EventHandlerOne[string]) {
+	a.reloaded.Subscribe(fn)
+}
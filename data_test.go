@@ -0,0 +1,122 @@
+package igloo_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/miniscruff/igloo"
+)
+
+type enemyData struct {
+	Name   string `json:"name" yaml:"name"`
+	Health int    `json:"health" yaml:"health"`
+}
+
+func (e *enemyData) Validate() error {
+	if e.Health <= 0 {
+		return errors.New("health must be positive")
+	}
+
+	return nil
+}
+
+func TestLoadData(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expected  enemyData
+		expectErr bool
+	}{
+		"json": {
+			path:     "slime.json",
+			expected: enemyData{Name: "slime", Health: 5},
+		},
+		"yaml": {
+			path:     "bat.yaml",
+			expected: enemyData{Name: "bat", Health: 3},
+		},
+		"invalid": {
+			path:      "ghost.json",
+			expectErr: true,
+		},
+		"unknown format": {
+			path:      "slime.txt",
+			expectErr: true,
+		},
+	}
+
+	fsys := fstest.MapFS{
+		"assets/slime.json": {Data: []byte(`{"name": "slime", "health": 5}`)},
+		"assets/bat.yaml":   {Data: []byte("name: bat\nhealth: 3\n")},
+		"assets/ghost.json": {Data: []byte(`{"name": "ghost", "health": 0}`)},
+		"assets/slime.txt":  {Data: []byte("slime")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := igloo.NewAssetLoader(fsys, "assets")
+
+			got, err := igloo.LoadData[enemyData](loader, tc.path)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if *got != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, *got)
+			}
+
+			cached, err := igloo.LoadData[enemyData](loader, tc.path)
+			if err != nil || cached != got {
+				t.Fatalf("expected cached value, got: %v %v", cached, err)
+			}
+		})
+	}
+}
+
+func TestReloadChangedData(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/slime.json": {
+			Data:    []byte(`{"name": "slime", "health": 5}`),
+			ModTime: time.Unix(100, 0),
+		},
+	}
+	loader := igloo.NewAssetLoader(fsys, "assets")
+
+	slime, err := igloo.LoadData[enemyData](loader, "slime.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var published []string
+
+	loader.OnReload(func(path string) {
+		published = append(published, path)
+	})
+
+	fsys["assets/slime.json"] = &fstest.MapFile{
+		Data:    []byte(`{"name": "big slime", "health": 12}`),
+		ModTime: time.Unix(200, 0),
+	}
+
+	reloaded, err := loader.ReloadChanged()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reloaded) != 1 || len(published) != 1 {
+		t.Fatalf("expected one reload, got: %v %v", reloaded, published)
+	}
+
+	if slime.Name != "big slime" || slime.Health != 12 {
+		t.Fatalf("expected value updated in place, got: %v", *slime)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"io/fs"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	ebaudio "github.com/hajimehoshi/ebiten/v2/audio"
 
+	"github.com/miniscruff/igloo/audio"
 	"github.com/miniscruff/igloo/mathf"
 )
 
@@ -24,13 +26,22 @@ type SceneContext struct {
 // GameConfig contains values you should set when initializing
 // that can only be configured at start.
 type GameConfig struct {
+	// Fsys to load assets from, use an assetfs.LayeredFS to allow
+	// mods and DLC to override embedded assets, or an assetfs.Archive
+	// packed with cmd/igloo-pack.
 	Fsys       fs.FS
 	AssetsPath string
+	// SampleRate of the audio context, leave at 0 to disable audio
+	SampleRate int
 }
 
 type Game struct {
 	scenes      []*SceneContext
 	assetLoader *AssetLoader
+	mixer       *audio.Mixer
+	postChain   *PostChain
+	screen      *ebiten.Image
+	sceneScreen *ebiten.Image
 
 	// window values
 	outsideWidth  int
@@ -68,6 +79,22 @@ func Height() int {
 	return game.screenHeight
 }
 
+// AudioMixer returns the game mixer, nil if the game was configured without audio
+func AudioMixer() *audio.Mixer {
+	return game.mixer
+}
+
+// PostProcessing returns the chain run over every scene, nil if disabled
+func PostProcessing() *PostChain {
+	return game.postChain
+}
+
+// SetPostProcessing draws all scenes offscreen and runs a chain over them
+// before presenting, set a nil chain to draw scenes straight to the screen.
+func SetPostProcessing(chain *PostChain) {
+	game.postChain = chain
+}
+
 func SetScreenSize(w, h int) {
 	game.screenWidth = w
 	game.screenHeight = h
@@ -101,9 +128,42 @@ func (g *Game) Update() (err error) {
 
 // Draw all the game scenes, bottom up
 func (g *Game) Draw(dest *ebiten.Image) {
+	if g.postChain == nil || !g.postChain.IsActive() {
+		for _, s := range g.scenes {
+			g.drawScene(dest, s.Scene)
+		}
+
+		return
+	}
+
+	g.screen = FitImage(g.screen, dest.Bounds().Size())
+	g.screen.Clear()
+
 	for _, s := range g.scenes {
-		s.Scene.Draw(dest)
+		g.drawScene(g.screen, s.Scene)
 	}
+
+	g.postChain.Draw(dest, g.screen)
+}
+
+// drawScene draws a scene through its own post chain if it has one
+func (g *Game) drawScene(dest *ebiten.Image, scene Scene) {
+	processor, ok := scene.(PostProcessor)
+	if !ok {
+		scene.Draw(dest)
+		return
+	}
+
+	chain := processor.PostChain()
+	if chain == nil || !chain.IsActive() {
+		scene.Draw(dest)
+		return
+	}
+
+	g.sceneScreen = FitImage(g.sceneScreen, dest.Bounds().Size())
+	g.sceneScreen.Clear()
+	scene.Draw(g.sceneScreen)
+	chain.Draw(dest, g.sceneScreen)
 }
 
 // Push a new scene to the top of the stack
@@ -113,6 +173,10 @@ func Push(scene Scene) {
 		Ticker: mathf.NewTicker(),
 	}
 
+	if game.mixer != nil {
+		context.Ticker.Add(game.mixer)
+	}
+
 	err := scene.Setup(game.assetLoader)
 	if err != nil {
 		panic(fmt.Errorf("setup: %w", err))
@@ -161,6 +225,10 @@ func InitGame(config GameConfig) {
 		assetLoader:  NewAssetLoader(config.Fsys, config.AssetsPath),
 	}
 	exit = false
+
+	if config.SampleRate > 0 {
+		game.mixer = audio.NewMixer(ebaudio.NewContext(config.SampleRate))
+	}
 }
 
 func Run() error {
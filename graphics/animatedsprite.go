@@ -0,0 +1,306 @@
+package graphics
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// DefaultFrameDuration is used for frames without a duration, in seconds
+const DefaultFrameDuration = 0.1
+
+// AnimationMode is what happens when a clip reaches its last frame
+type AnimationMode string
+
+const (
+	// AnimationLoop starts again from the first frame
+	AnimationLoop AnimationMode = "loop"
+	// AnimationPingPong plays backwards to the first frame and repeats
+	AnimationPingPong AnimationMode = "pingpong"
+	// AnimationOnce stops on the last frame
+	AnimationOnce AnimationMode = "once"
+)
+
+type animationClip struct {
+	animation *content.Animation
+	mode      AnimationMode
+}
+
+// frame returns a frame accounting for reversed animations
+func (c *animationClip) frame(index int) content.Frame {
+	if c.animation.Direction == content.AnimationReverse {
+		index = len(c.animation.Frames) - 1 - index
+	}
+
+	return c.animation.Frames[index]
+}
+
+// AnimatedSpriteVisual plays named animation clips, it needs to be added
+// to the scene ticker to advance frames.
+type AnimatedSpriteVisual struct {
+	*igloo.Visualer
+
+	clips         map[string]*animationClip
+	clip          *animationClip
+	frame         int
+	step          int
+	elapsed       float64
+	speed         float64
+	frameDuration float64
+	isPaused      bool
+	isDirty       bool
+
+	frameChanged igloo.EventStoreTwo[string, int]
+	completed    igloo.EventStoreOne[string]
+}
+
+func NewAnimatedSpriteVisual() *AnimatedSpriteVisual {
+	v := &AnimatedSpriteVisual{
+		clips:         make(map[string]*animationClip),
+		step:          1,
+		speed:         1,
+		frameDuration: DefaultFrameDuration,
+		isPaused:      true,
+		isDirty:       true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+// AddClip adds an animation that can be played by its name.
+// Animations with a ping pong direction default to the ping pong mode.
+func (v *AnimatedSpriteVisual) AddClip(animation *content.Animation, mode AnimationMode) {
+	if mode == "" {
+		mode = AnimationLoop
+		if animation.Direction == content.AnimationPingPong {
+			mode = AnimationPingPong
+		}
+	}
+
+	v.clips[animation.Name] = &animationClip{
+		animation: animation,
+		mode:      mode,
+	}
+}
+
+// Play starts a clip from its first frame,
+// returns false if there is no clip with that name.
+func (v *AnimatedSpriteVisual) Play(name string) bool {
+	clip, ok := v.clips[name]
+	if !ok || len(clip.animation.Frames) == 0 {
+		return false
+	}
+
+	v.clip = clip
+	v.elapsed = 0
+	v.step = 1
+	v.isPaused = false
+	v.setFrame(0)
+
+	return true
+}
+
+// Clip returns the name of the current clip or an empty string
+func (v *AnimatedSpriteVisual) Clip() string {
+	if v.clip == nil {
+		return ""
+	}
+
+	return v.clip.animation.Name
+}
+
+// Frame returns the index of the current frame in play order
+func (v *AnimatedSpriteVisual) Frame() int {
+	return v.frame
+}
+
+// Speed is the playback rate, 1 is normal speed
+func (v *AnimatedSpriteVisual) Speed() float64 {
+	return v.speed
+}
+
+// SetSpeed changes the playback rate, negative speeds are treated as zero
+func (v *AnimatedSpriteVisual) SetSpeed(speed float64) {
+	if speed < 0 {
+		speed = 0
+	}
+
+	v.speed = speed
+}
+
+// SetFrameDuration sets the duration in seconds of frames without a duration
+func (v *AnimatedSpriteVisual) SetFrameDuration(duration float64) {
+	v.frameDuration = duration
+}
+
+// OnFrame subscribes to frame changes with the clip name and frame index
+func (v *AnimatedSpriteVisual) OnFrame(fn igloo.EventHandlerTwo[string, int]) {
+	v.frameChanged.Subscribe(fn)
+}
+
+// OnCompleted subscribes to clips finishing, once clips complete on their
+// last frame while loop and ping pong clips complete every cycle.
+func (v *AnimatedSpriteVisual) OnCompleted(fn igloo.EventHandlerOne[string]) {
+	v.completed.Subscribe(fn)
+}
+
+func (v *AnimatedSpriteVisual) Resume() {
+	if v.clip != nil {
+		v.isPaused = false
+	}
+}
+
+func (v *AnimatedSpriteVisual) Pause() {
+	v.isPaused = true
+}
+
+func (v *AnimatedSpriteVisual) IsPaused() bool {
+	return v.isPaused
+}
+
+func (v *AnimatedSpriteVisual) Tick() {
+	if v.clip == nil || v.isPaused {
+		return
+	}
+
+	v.elapsed += mathf.TickDelta() * v.speed
+
+	for !v.isPaused {
+		duration := v.clip.frame(v.frame).Duration
+		if duration <= 0 {
+			duration = v.frameDuration
+		}
+
+		// without any duration we can only move one frame per tick
+		if duration <= 0 {
+			v.elapsed = 0
+			v.advance()
+
+			return
+		}
+
+		if v.elapsed < duration {
+			return
+		}
+
+		v.elapsed -= duration
+		v.advance()
+	}
+}
+
+// advance moves to the next frame based on our mode
+func (v *AnimatedSpriteVisual) advance() {
+	name := v.clip.animation.Name
+	last := len(v.clip.animation.Frames) - 1
+
+	switch v.clip.mode {
+	case AnimationOnce:
+		if v.frame >= last {
+			v.isPaused = true
+			v.elapsed = 0
+			v.completed.Publish(name)
+
+			return
+		}
+
+		v.setFrame(v.frame + 1)
+	case AnimationPingPong:
+		if last == 0 {
+			v.completed.Publish(name)
+			return
+		}
+
+		if v.frame+v.step < 0 || v.frame+v.step > last {
+			v.step = -v.step
+		}
+
+		v.setFrame(v.frame + v.step)
+
+		if v.frame == 0 {
+			v.completed.Publish(name)
+		}
+	default:
+		if v.frame >= last {
+			v.setFrame(0)
+			v.completed.Publish(name)
+
+			return
+		}
+
+		v.setFrame(v.frame + 1)
+	}
+}
+
+func (v *AnimatedSpriteVisual) setFrame(index int) {
+	previous := v.sprite()
+	v.frame = index
+
+	current := v.sprite()
+	if previous == nil || current == nil {
+		v.isDirty = true
+	} else {
+		pw, ph := previous.NativeSize()
+		cw, ch := current.NativeSize()
+		v.isDirty = v.isDirty || pw != cw || ph != ch
+	}
+
+	v.frameChanged.Publish(v.clip.animation.Name, index)
+}
+
+// sprite returns the sprite of our current frame or nil if not playing
+func (v *AnimatedSpriteVisual) sprite() *content.Sprite {
+	if v.clip == nil {
+		return nil
+	}
+
+	return v.clip.frame(v.frame).Sprite
+}
+
+func (v *AnimatedSpriteVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *AnimatedSpriteVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *AnimatedSpriteVisual) NativeSize() (float64, float64) {
+	sprite := v.sprite()
+	if sprite == nil {
+		return 0, 0
+	}
+
+	return sprite.NativeSize()
+}
+
+// DrawBatch adds our sprite to a batch instead of drawing on our own
+func (v *AnimatedSpriteVisual) DrawBatch(batch *SpriteBatch) {
+	batch.Add(v.sprite(), v.Transform.GeoM())
+}
+
+func (v *AnimatedSpriteVisual) Draw(dest *ebiten.Image) {
+	sprite := v.sprite()
+	if sprite == nil {
+		return
+	}
+
+	geom := sprite.RegionGeoM()
+	geom.Concat(v.Transform.GeoM())
+
+	dest.DrawImage(sprite.Image, &ebiten.DrawImageOptions{
+		GeoM:          geom,
+		ColorM:        sprite.ColorM,
+		Filter:        sprite.Filter,
+		CompositeMode: sprite.CompositeMode,
+	})
+}
@@ -0,0 +1,119 @@
+package graphics_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestSheetFromGrid(t *testing.T) {
+	sheet := ebiten.NewImage(6, 4)
+	sprites := graphics.SheetFromGrid(sheet, 3, 2, 5)
+
+	if len(sprites) != 5 {
+		t.Fatalf("expected: 5, got: %v", len(sprites))
+	}
+
+	expected := image.Rect(2, 2, 4, 4)
+	if got := sprites[4].Image.Bounds(); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+}
+
+func TestAnimatedSpriteVisual(t *testing.T) {
+	tests := map[string]struct {
+		mode      graphics.AnimationMode
+		speed     float64
+		ticks     int
+		frame     int
+		completed int
+		paused    bool
+	}{
+		"loop": {
+			mode:      graphics.AnimationLoop,
+			ticks:     4,
+			frame:     1,
+			completed: 1,
+		},
+		"ping pong back to start": {
+			mode:      graphics.AnimationPingPong,
+			ticks:     4,
+			frame:     0,
+			completed: 1,
+		},
+		"ping pong second cycle": {
+			mode:      graphics.AnimationPingPong,
+			ticks:     5,
+			frame:     1,
+			completed: 1,
+		},
+		"once": {
+			mode:      graphics.AnimationOnce,
+			ticks:     5,
+			frame:     2,
+			completed: 1,
+			paused:    true,
+		},
+		"double speed": {
+			mode:  graphics.AnimationLoop,
+			speed: 2,
+			ticks: 1,
+			frame: 2,
+		},
+	}
+
+	sprites := graphics.SheetFromGrid(ebiten.NewImage(3, 1), 3, 1, 3)
+	anim := graphics.NewAnimationFromSprites("walk", sprites, mathf.TickDelta())
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := graphics.NewAnimatedSpriteVisual()
+			v.AddClip(anim, tc.mode)
+
+			if tc.speed != 0 {
+				v.SetSpeed(tc.speed)
+			}
+
+			completed := 0
+			v.OnCompleted(func(clip string) {
+				completed++
+			})
+
+			if !v.Play("walk") {
+				t.Fatal("expected walk clip to play")
+			}
+
+			for i := 0; i < tc.ticks; i++ {
+				v.Tick()
+			}
+
+			if v.Frame() != tc.frame {
+				t.Fatalf("expected: %v, got: %v", tc.frame, v.Frame())
+			}
+
+			if completed != tc.completed {
+				t.Fatalf("expected: %v, got: %v", tc.completed, completed)
+			}
+
+			if v.IsPaused() != tc.paused {
+				t.Fatalf("expected: %v, got: %v", tc.paused, v.IsPaused())
+			}
+		})
+	}
+}
+
+func TestAnimatedSpriteVisualMissingClip(t *testing.T) {
+	v := graphics.NewAnimatedSpriteVisual()
+	v.AddClip(&content.Animation{Name: "empty"}, "")
+
+	for _, name := range []string{"missing", "empty"} {
+		if v.Play(name) {
+			t.Fatalf("expected: %v to not play", name)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package graphics
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+)
+
+// maxBatchSprites is the most sprites one DrawTriangles call can index
+const maxBatchSprites = content.MaxQuads
+
+// Batcher is an optional interface for drawers that can add themselves
+// to a sprite batch instead of drawing on their own.
+type Batcher interface {
+	DrawBatch(batch *SpriteBatch)
+}
+
+// SpriteBatch draws many sprites with as few DrawTriangles calls as
+// possible. Sprites are drawn together while they share the same page
+// image, color matrix, composite mode and filter, so different frames cut
+// from one sheet or atlas page draw together.
+type SpriteBatch struct {
+	dest          *ebiten.Image
+	sprite        *content.Sprite
+	image         *ebiten.Image
+	colorM        ebiten.ColorM
+	compositeMode ebiten.CompositeMode
+	filter        ebiten.Filter
+	vertices      []ebiten.Vertex
+	indices       []uint16
+	drawCalls     int
+}
+
+func NewSpriteBatch() *SpriteBatch {
+	return &SpriteBatch{
+		vertices: make([]ebiten.Vertex, 0),
+		indices:  make([]uint16, 0),
+	}
+}
+
+// DrawCalls returns how many draw calls were made since our last Draw or Begin
+func (b *SpriteBatch) DrawCalls() int {
+	return b.drawCalls
+}
+
+// Begin starts batching sprites drawn into dest
+func (b *SpriteBatch) Begin(dest *ebiten.Image) {
+	b.dest = dest
+	b.drawCalls = 0
+}
+
+// Add a sprite drawn with geom, geom is applied after the sprite region.
+// Anything batched that does not share state with our sprite is drawn first.
+func (b *SpriteBatch) Add(sprite *content.Sprite, geom ebiten.GeoM) {
+	if sprite == nil || sprite.Image == nil {
+		return
+	}
+
+	if len(b.vertices)/4 >= maxBatchSprites || !b.matches(sprite) {
+		b.Flush()
+
+		b.sprite = sprite
+		b.image = sprite.PageImage()
+		b.colorM = sprite.ColorM
+		b.compositeMode = sprite.CompositeMode
+		b.filter = sprite.Filter
+	}
+
+	region := sprite.RegionGeoM()
+	region.Concat(geom)
+
+	// sub image bounds are already where we are on our page
+	src := sprite.Image.Bounds()
+	width := float64(src.Dx())
+	height := float64(src.Dy())
+	start := uint16(len(b.vertices))
+
+	corners := [4]struct{ x, y, srcX, srcY float64 }{
+		{0, 0, float64(src.Min.X), float64(src.Min.Y)},
+		{width, 0, float64(src.Max.X), float64(src.Min.Y)},
+		{0, height, float64(src.Min.X), float64(src.Max.Y)},
+		{width, height, float64(src.Max.X), float64(src.Max.Y)},
+	}
+
+	for _, c := range corners {
+		x, y := region.Apply(c.x, c.y)
+
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(c.srcX),
+			SrcY:   float32(c.srcY),
+			ColorR: 1,
+			ColorG: 1,
+			ColorB: 1,
+			ColorA: 1,
+		})
+	}
+
+	b.indices = append(b.indices, start, start+1, start+2, start+1, start+3, start+2)
+}
+
+// matches checks if a sprite can be drawn with what we have batched
+func (b *SpriteBatch) matches(sprite *content.Sprite) bool {
+	if b.sprite == sprite {
+		return true
+	}
+
+	if b.image != sprite.PageImage() ||
+		b.compositeMode != sprite.CompositeMode ||
+		b.filter != sprite.Filter {
+		return false
+	}
+
+	for i := 0; i < ebiten.ColorMDim-1; i++ {
+		for j := 0; j < ebiten.ColorMDim; j++ {
+			if b.colorM.Element(i, j) != sprite.ColorM.Element(i, j) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Flush draws everything batched so far
+func (b *SpriteBatch) Flush() {
+	if len(b.indices) == 0 {
+		return
+	}
+
+	b.dest.DrawTriangles(b.vertices, b.indices, b.image, &ebiten.DrawTrianglesOptions{
+		ColorM:        b.colorM,
+		CompositeMode: b.compositeMode,
+		Filter:        b.filter,
+	})
+
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+	b.sprite = nil
+	b.image = nil
+	b.drawCalls++
+}
+
+// Draw a visual tree in the same order as Visualer.Draw, batching every
+// visual with a Batcher and drawing the rest normally in between.
+func (b *SpriteBatch) Draw(dest *ebiten.Image, root *igloo.Visualer) {
+	b.Begin(dest)
+	b.add(root)
+	b.Flush()
+}
+
+func (b *SpriteBatch) add(v *igloo.Visualer) {
+	if !v.Visible() {
+		return
+	}
+
+	if childDrawer, ok := v.Drawer.(igloo.ChildDrawer); ok {
+		b.Flush()
+		childDrawer.DrawWithChildren(b.dest)
+
+		return
+	}
+
+	if v.DrawParentFirst() {
+		b.addDrawer(v.Drawer)
+	}
+
+	for _, child := range v.DrawOrder() {
+		b.add(child)
+	}
+
+	if !v.DrawParentFirst() {
+		b.addDrawer(v.Drawer)
+	}
+}
+
+func (b *SpriteBatch) addDrawer(drawer igloo.Drawer) {
+	if batcher, ok := drawer.(Batcher); ok {
+		batcher.DrawBatch(b)
+		return
+	}
+
+	// keep our draw order by drawing what we have first
+	b.Flush()
+	drawer.Draw(b.dest)
+}
@@ -0,0 +1,163 @@
+package graphics_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// newSpriteTree creates a visible root with a child for each sprite
+func newSpriteTree(sprites []*content.Sprite) *igloo.Visualer {
+	transform := mathf.NewTransform()
+	transform.SetSize(640, 480)
+	transform.SetNaturalWidth(640)
+	transform.SetNaturalHeight(480)
+	transform.Build(nil)
+
+	root := graphics.NewEmptyVisual()
+	root.SetVisible(true)
+
+	for i, sprite := range sprites {
+		child := graphics.NewSpriteVisual()
+		child.SetSprite(sprite)
+		child.Transform.SetPosition(mathf.Vec2{X: float64(i % 640), Y: float64(i % 480)})
+		child.SetVisible(true)
+		root.InsertChild(child.Visualer)
+	}
+
+	root.Layout(transform, transform)
+
+	return root.Visualer
+}
+
+func TestSpriteBatch(t *testing.T) {
+	bullet := &content.Sprite{Image: ebiten.NewImage(4, 4)}
+	sameImage := &content.Sprite{Image: bullet.Image}
+	tinted := &content.Sprite{Image: bullet.Image}
+	tinted.ColorM.Scale(1, 0, 0, 1)
+	ship := &content.Sprite{Image: ebiten.NewImage(8, 8)}
+	frames := graphics.SheetFromGrid(ebiten.NewImage(8, 4), 2, 1, 2)
+	otherFrames := graphics.SheetFromGrid(ebiten.NewImage(8, 4), 2, 1, 2)
+
+	tests := map[string]struct {
+		sprites   []*content.Sprite
+		drawCalls int
+	}{
+		"nothing to draw": {
+			sprites:   []*content.Sprite{},
+			drawCalls: 0,
+		},
+		"same sprite": {
+			sprites:   []*content.Sprite{bullet, bullet, bullet},
+			drawCalls: 1,
+		},
+		"same image and state": {
+			sprites:   []*content.Sprite{bullet, sameImage, bullet},
+			drawCalls: 1,
+		},
+		"different color matrix": {
+			sprites:   []*content.Sprite{bullet, tinted, bullet},
+			drawCalls: 3,
+		},
+		"frames from one sheet": {
+			sprites:   []*content.Sprite{frames[0], frames[1], frames[0]},
+			drawCalls: 1,
+		},
+		"frames from different sheets": {
+			sprites:   []*content.Sprite{frames[0], otherFrames[1], frames[1]},
+			drawCalls: 3,
+		},
+		"different image": {
+			sprites:   []*content.Sprite{bullet, bullet, ship, ship, bullet},
+			drawCalls: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := newSpriteTree(tc.sprites)
+			batch := graphics.NewSpriteBatch()
+			batch.Draw(ebiten.NewImage(640, 480), root)
+
+			if batch.DrawCalls() != tc.drawCalls {
+				t.Fatalf("expected: %v, got: %v", tc.drawCalls, batch.DrawCalls())
+			}
+		})
+	}
+}
+
+func TestSpriteBatchDrawsOthersInOrder(t *testing.T) {
+	bullet := &content.Sprite{Image: ebiten.NewImage(4, 4)}
+	root := newSpriteTree([]*content.Sprite{bullet, bullet})
+	other := newCountingVisual()
+	other.SetVisible(true)
+	root.InsertChild(other.Visualer)
+	root.InsertChild(newSpriteTree([]*content.Sprite{bullet}))
+
+	batch := graphics.NewSpriteBatch()
+	batch.Draw(ebiten.NewImage(640, 480), root)
+
+	if other.draws != 1 {
+		t.Fatalf("expected: 1 draw, got: %v", other.draws)
+	}
+
+	if batch.DrawCalls() != 2 {
+		t.Fatalf("expected: 2, got: %v", batch.DrawCalls())
+	}
+}
+
+func TestSpriteBatchDrawsSheetFrames(t *testing.T) {
+	sheet := ebiten.NewImage(8, 4)
+	sheet.SubImage(image.Rect(4, 0, 8, 4)).(*ebiten.Image).Fill(color.White)
+
+	frames := graphics.SheetFromGrid(sheet, 2, 1, 2)
+	dest := ebiten.NewImage(640, 480)
+	batch := graphics.NewSpriteBatch()
+	batch.Draw(dest, newSpriteTree([]*content.Sprite{frames[1]}))
+
+	// the second frame is white so our first pixel should be too
+	if got := pixelAt(t, dest, 1, 1); got.A != 0xff {
+		t.Fatalf("expected: opaque pixel, got: %v", got)
+	}
+}
+
+func benchmarkSprites() *igloo.Visualer {
+	bullet := &content.Sprite{Image: ebiten.NewImage(4, 4)}
+	sprites := make([]*content.Sprite, 10000)
+
+	for i := range sprites {
+		sprites[i] = bullet
+	}
+
+	return newSpriteTree(sprites)
+}
+
+func BenchmarkSpritesUnbatched(b *testing.B) {
+	root := benchmarkSprites()
+	dest := ebiten.NewImage(640, 480)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		root.Draw(dest)
+	}
+}
+
+func BenchmarkSpritesBatched(b *testing.B) {
+	root := benchmarkSprites()
+	dest := ebiten.NewImage(640, 480)
+	batch := graphics.NewSpriteBatch()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		batch.Draw(dest, root)
+	}
+}
@@ -0,0 +1,166 @@
+package graphics
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// CanvasVisual draws its children into an offscreen image and draws that
+// image with its own Transform. Children are only redrawn when something
+// in our subtree changes during layout, visuals that change without being
+// dirty, such as playing animations, need Redraw to be called.
+// Children outside of our bounds are cut off.
+type CanvasVisual struct {
+	*igloo.Visualer
+	ebiten.ColorM
+	ebiten.Filter
+
+	image       *ebiten.Image
+	contentView ebiten.GeoM
+	shader      *ebiten.Shader
+	uniforms    ShaderUniforms
+	width       float64
+	height      float64
+	redraw      bool
+	isDirty     bool
+}
+
+func NewCanvasVisual() *CanvasVisual {
+	v := &CanvasVisual{
+		uniforms: make(ShaderUniforms),
+		redraw:   true,
+		isDirty:  true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	v.Transform.SetContentView(&v.contentView)
+
+	return v
+}
+
+// SetSize sets our native size
+func (v *CanvasVisual) SetSize(width, height float64) {
+	if v.width == width && v.height == height {
+		return
+	}
+
+	v.width = width
+	v.height = height
+	v.isDirty = true
+}
+
+// Image returns the image our children were last drawn into
+func (v *CanvasVisual) Image() *ebiten.Image {
+	return v.image
+}
+
+// Redraw draws our children again on our next draw
+func (v *CanvasVisual) Redraw() {
+	v.redraw = true
+}
+
+// SetShader draws our image with a shader, our image is the first source
+// image of the shader. Set a nil shader to draw our image normally.
+func (v *CanvasVisual) SetShader(shader *ebiten.Shader) {
+	v.shader = shader
+}
+
+// Uniforms returns the uniforms passed to our shader
+func (v *CanvasVisual) Uniforms() ShaderUniforms {
+	return v.uniforms
+}
+
+func (v *CanvasVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *CanvasVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *CanvasVisual) NativeSize() (float64, float64) {
+	return v.width, v.height
+}
+
+// DrawWithChildren redraws our children if needed and then draws our image
+func (v *CanvasVisual) DrawWithChildren(dest *ebiten.Image) {
+	bounds := v.Transform.Bounds()
+	width := int(math.Ceil(bounds.Width))
+	height := int(math.Ceil(bounds.Height))
+
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	if v.image == nil || v.image.Bounds().Dx() != width || v.image.Bounds().Dy() != height {
+		if v.image != nil {
+			v.image.Dispose()
+		}
+
+		v.image = ebiten.NewImage(width, height)
+		v.redraw = true
+	}
+
+	if v.redraw || v.SubtreeChanged() {
+		v.contentView.Reset()
+		v.contentView.Translate(-bounds.X, -bounds.Y)
+		v.image.Clear()
+
+		for _, child := range v.DrawOrder() {
+			child.Draw(v.image)
+		}
+
+		v.redraw = false
+		v.ClearSubtreeChanged()
+	}
+
+	v.Draw(dest)
+}
+
+// Draw draws our last image without redrawing our children
+func (v *CanvasVisual) Draw(dest *ebiten.Image) {
+	if v.image == nil {
+		return
+	}
+
+	bounds := v.Transform.Bounds()
+	nativeWidth, nativeHeight := v.Transform.NaturalSize()
+
+	if nativeWidth <= 0 || nativeHeight <= 0 {
+		return
+	}
+
+	// draw in our final size by undoing the scale of our transform
+	var geom ebiten.GeoM
+
+	geom.Scale(nativeWidth/bounds.Width, nativeHeight/bounds.Height)
+	geom.Concat(v.Transform.GeoM())
+
+	if v.shader != nil {
+		size := v.image.Bounds().Size()
+		dest.DrawRectShader(size.X, size.Y, v.shader, &ebiten.DrawRectShaderOptions{
+			GeoM:     geom,
+			Uniforms: v.uniforms,
+			Images:   [4]*ebiten.Image{v.image},
+		})
+
+		return
+	}
+
+	dest.DrawImage(v.image, &ebiten.DrawImageOptions{
+		GeoM:   geom,
+		ColorM: v.ColorM,
+		Filter: v.Filter,
+	})
+}
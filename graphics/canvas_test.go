@@ -0,0 +1,138 @@
+package graphics_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// countingVisual counts how many times it was drawn
+type countingVisual struct {
+	*igloo.Visualer
+	draws   int
+	isDirty bool
+}
+
+func newCountingVisual() *countingVisual {
+	v := &countingVisual{isDirty: true}
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+func (v *countingVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *countingVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *countingVisual) NativeSize() (float64, float64) {
+	return 4, 4
+}
+
+func (v *countingVisual) Draw(dest *ebiten.Image) {
+	v.draws++
+}
+
+func TestCanvasVisual(t *testing.T) {
+	root := mathf.NewTransform()
+	root.SetSize(64, 64)
+	root.SetNaturalWidth(64)
+	root.SetNaturalHeight(64)
+	root.Build(nil)
+
+	canvas := graphics.NewCanvasVisual()
+	canvas.SetSize(16, 16)
+	canvas.Transform.SetPosition(mathf.Vec2{X: 8, Y: 8})
+	canvas.SetVisible(true)
+
+	child := newCountingVisual()
+	canvas.InsertChild(child.Visualer)
+
+	dest := ebiten.NewImage(64, 64)
+	frame := func() {
+		canvas.Layout(root, root)
+		canvas.Visualer.Draw(dest)
+	}
+
+	frame()
+	frame()
+
+	if child.draws != 1 {
+		t.Fatalf("expected: 1 draw while unchanged, got: %v", child.draws)
+	}
+
+	// children are drawn relative to the canvas
+	geom := child.Transform.GeoM()
+	if x, y := geom.Apply(0, 0); x != 0 || y != 0 {
+		t.Fatalf("expected: 0, 0, got: %v, %v", x, y)
+	}
+
+	child.isDirty = true
+	frame()
+
+	canvas.Redraw()
+	frame()
+
+	if child.draws != 3 {
+		t.Fatalf("expected: 3 draws after changes, got: %v", child.draws)
+	}
+}
+
+func TestCanvasVisualRedrawsOnZIndex(t *testing.T) {
+	root := mathf.NewTransform()
+	root.SetSize(32, 32)
+	root.SetNaturalWidth(32)
+	root.SetNaturalHeight(32)
+	root.Build(nil)
+
+	canvas := graphics.NewCanvasVisual()
+	canvas.SetSize(16, 16)
+	canvas.SetVisible(true)
+
+	newSquare := func(fill color.Color) *graphics.ShapeVisual {
+		square := graphics.NewShapeVisual(graphics.RectShape{})
+		square.SetSize(16, 16)
+		square.SetFill(fill)
+		square.SetVisible(true)
+		canvas.InsertChild(square.Visualer)
+
+		return square
+	}
+
+	red := newSquare(color.RGBA{R: 255, A: 255})
+	newSquare(color.RGBA{B: 255, A: 255})
+
+	dest := ebiten.NewImage(32, 32)
+	frame := func() {
+		canvas.Layout(root, root)
+		dest.Clear()
+		canvas.Visualer.Draw(dest)
+	}
+
+	frame()
+
+	if got := pixelAt(t, dest, 8, 8); got.B != 255 || got.R != 0 {
+		t.Fatalf("expected: blue on top, got: %v", got)
+	}
+
+	red.SetZIndex(1)
+	frame()
+
+	if got := pixelAt(t, dest, 8, 8); got.R != 255 || got.B != 0 {
+		t.Fatalf("expected: red on top after z index change, got: %v", got)
+	}
+}
@@ -0,0 +1,169 @@
+package graphics
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// ClipVisual only lets its children draw inside of its Transform.
+// Without rotation or a mask, children draw straight into part of the
+// destination. Otherwise they draw offscreen and are masked by our rotated
+// rectangle or by the alpha of a mask sprite stretched over our Transform.
+// Hit tests outside of our Transform skip our children.
+type ClipVisual struct {
+	*igloo.Visualer
+
+	mask        *content.Sprite
+	contentView ebiten.GeoM
+	offscreen   *ebiten.Image
+	maskScreen  *ebiten.Image
+	width       float64
+	height      float64
+	isDirty     bool
+}
+
+func NewClipVisual() *ClipVisual {
+	v := &ClipVisual{
+		isDirty: true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	v.Transform.SetContentView(&v.contentView)
+
+	// we have nothing to draw so our children are always on top
+	v.SetDrawParentFirst(true)
+
+	return v
+}
+
+// SetSize sets our native size
+func (v *ClipVisual) SetSize(width, height float64) {
+	if v.width == width && v.height == height {
+		return
+	}
+
+	v.width = width
+	v.height = height
+	v.isDirty = true
+}
+
+func (v *ClipVisual) Mask() *content.Sprite {
+	return v.mask
+}
+
+// SetMask clips children to the alpha of a sprite, nil clips to our rectangle
+func (v *ClipVisual) SetMask(mask *content.Sprite) {
+	v.mask = mask
+}
+
+func (v *ClipVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *ClipVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *ClipVisual) NativeSize() (float64, float64) {
+	return v.width, v.height
+}
+
+// AllowsHit checks if a world point is inside of our rotated rectangle,
+// masks are not checked so the whole rectangle can be hit.
+func (v *ClipVisual) AllowsHit(point mathf.Vec2) bool {
+	geom := v.Transform.WorldGeoM()
+	if !geom.IsInvertible() {
+		return false
+	}
+
+	geom.Invert()
+
+	x, y := geom.Apply(point.X, point.Y)
+	width, height := v.Transform.NaturalSize()
+
+	return x >= 0 && y >= 0 && x < width && y < height
+}
+
+// Draw does nothing as we only clip our children
+func (v *ClipVisual) Draw(dest *ebiten.Image) {}
+
+func (v *ClipVisual) DrawWithChildren(dest *ebiten.Image) {
+	v.contentView = v.Transform.View()
+	geom := v.Transform.GeoM()
+	clip := v.Transform.ScreenBounds()
+	rect := image.Rect(
+		int(math.Floor(clip.X)),
+		int(math.Floor(clip.Y)),
+		int(math.Ceil(clip.Right())),
+		int(math.Ceil(clip.Bottom())),
+	).Intersect(dest.Bounds())
+
+	if rect.Empty() {
+		return
+	}
+
+	// sub images keep the coordinates of the destination
+	if v.mask == nil && geom.Element(0, 1) == 0 && geom.Element(1, 0) == 0 {
+		v.drawChildren(dest.SubImage(rect).(*ebiten.Image))
+		return
+	}
+
+	v.drawMasked(dest, rect, geom)
+}
+
+func (v *ClipVisual) drawChildren(dest *ebiten.Image) {
+	for _, child := range v.DrawOrder() {
+		child.Draw(dest)
+	}
+}
+
+// drawMasked draws our children offscreen at the size of our screen bounds
+// then removes anything outside of our mask before drawing to dest.
+func (v *ClipVisual) drawMasked(dest *ebiten.Image, rect image.Rectangle, geom ebiten.GeoM) {
+	size := rect.Size()
+
+	v.offscreen = igloo.FitImage(v.offscreen, size)
+	v.offscreen.Clear()
+	v.contentView.Translate(-float64(rect.Min.X), -float64(rect.Min.Y))
+	v.drawChildren(v.offscreen)
+
+	width, height := v.Transform.NaturalSize()
+	maskImage := whiteShapeImage()
+
+	if v.mask != nil {
+		maskImage = v.mask.Image
+	}
+
+	maskSize := maskImage.Bounds().Size()
+	maskOpts := &ebiten.DrawImageOptions{}
+
+	maskOpts.GeoM.Scale(width/float64(maskSize.X), height/float64(maskSize.Y))
+	maskOpts.GeoM.Concat(geom)
+	maskOpts.GeoM.Translate(-float64(rect.Min.X), -float64(rect.Min.Y))
+
+	// composite modes only affect pixels under the source, so our mask is
+	// drawn into a cleared image covering all of our offscreen image first
+	v.maskScreen = igloo.FitImage(v.maskScreen, size)
+	v.maskScreen.Clear()
+	v.maskScreen.DrawImage(maskImage, maskOpts)
+	v.offscreen.DrawImage(v.maskScreen, &ebiten.DrawImageOptions{
+		CompositeMode: ebiten.CompositeModeDestinationIn,
+	})
+
+	drawOpts := &ebiten.DrawImageOptions{}
+	drawOpts.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	dest.DrawImage(v.offscreen, drawOpts)
+}
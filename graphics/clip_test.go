@@ -0,0 +1,113 @@
+package graphics_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func newClipTest(rotation float64) (*mathf.Transform, *graphics.ClipVisual, *countingVisual) {
+	root := mathf.NewTransform()
+	root.SetSize(64, 64)
+	root.SetNaturalWidth(64)
+	root.SetNaturalHeight(64)
+	root.Build(nil)
+
+	clip := graphics.NewClipVisual()
+	clip.SetSize(16, 16)
+	clip.Transform.SetPosition(mathf.Vec2{X: 8, Y: 8})
+	clip.Transform.SetRotation(rotation)
+	clip.SetVisible(true)
+
+	child := newCountingVisual()
+	child.Transform.SetPosition(mathf.Vec2{X: 14, Y: 14})
+	clip.InsertChild(child.Visualer)
+	clip.Layout(root, root)
+
+	return root, clip, child
+}
+
+func TestClipVisualDraw(t *testing.T) {
+	for name, rotation := range map[string]float64{
+		"axis aligned": 0,
+		"rotated":      math.Pi / 4,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, clip, child := newClipTest(rotation)
+			dest := ebiten.NewImage(64, 64)
+
+			clip.Visualer.Draw(dest)
+
+			if child.draws != 1 {
+				t.Fatalf("expected: 1 draw, got: %v", child.draws)
+			}
+		})
+	}
+}
+
+func TestClipVisualHitTest(t *testing.T) {
+	_, clip, child := newClipTest(0)
+
+	tests := map[string]struct {
+		point    mathf.Vec2
+		expected *igloo.Visualer
+	}{
+		"child inside clip": {
+			point:    mathf.Vec2{X: 23, Y: 23},
+			expected: child.Visualer,
+		},
+		"child outside clip": {
+			point:    mathf.Vec2{X: 25, Y: 25},
+			expected: nil,
+		},
+		"clip without child": {
+			point:    mathf.Vec2{X: 10, Y: 10},
+			expected: clip.Visualer,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if hit := clip.HitTest(tc.point); hit != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, hit)
+			}
+		})
+	}
+}
+
+func TestClipVisualClipsRotatedCorners(t *testing.T) {
+	root, clip, _ := newClipTest(math.Pi / 4)
+
+	// a child covering far more than our clip
+	fill := ebiten.NewImage(64, 64)
+	fill.Fill(color.White)
+
+	child := graphics.NewSpriteVisual()
+	child.SetSprite(&content.Sprite{Image: fill})
+	child.Transform.SetPosition(mathf.Vec2{X: -24, Y: -24})
+	child.SetVisible(true)
+	clip.InsertChild(child.Visualer)
+	clip.Layout(root, root)
+
+	dest := ebiten.NewImage(64, 64)
+	clip.Visualer.Draw(dest)
+
+	bounds := clip.Transform.ScreenBounds()
+	centerX := int(bounds.X + bounds.Width/2)
+	centerY := int(bounds.Y + bounds.Height/2)
+
+	if clr := pixelAt(t, dest, centerX, centerY); clr.A != 0xff {
+		t.Fatalf("expected: opaque center, got: %v", clr)
+	}
+
+	if clr := pixelAt(t, dest, int(bounds.X)+1, int(bounds.Y)+1); clr.A != 0 {
+		t.Fatalf("expected: transparent corner, got: %v", clr)
+	}
+}
@@ -37,3 +37,7 @@ func (v *EmptyVisual) NativeSizer() (float64, float64) {
 
 func (v *EmptyVisual) Draw(dest *ebiten.Image) {
 }
+
+// DrawBatch does nothing so empty containers never break up a batch
+func (v *EmptyVisual) DrawBatch(batch *SpriteBatch) {
+}
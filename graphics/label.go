@@ -1,29 +1,103 @@
 package graphics
 
 import (
+	"image"
+	"math"
+	"strings"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
 
 	"github.com/miniscruff/igloo"
 	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/locale"
 	"github.com/miniscruff/igloo/mathf"
 )
 
-// probably want a text render mode like scale, word wrap or something
-// otherwise the size is just based on the text input
+// TextAlign is how lines are placed horizontally inside of a label
+type TextAlign string
+
+const (
+	AlignLeft   TextAlign = "left"
+	AlignCenter TextAlign = "center"
+	AlignRight  TextAlign = "right"
+	// AlignJustify stretches the space between words of wrapped lines,
+	// the last line of each paragraph is left aligned.
+	AlignJustify TextAlign = "justify"
+)
+
+// TextVerticalAlign is how all lines are placed vertically inside of a label
+type TextVerticalAlign string
+
+const (
+	AlignTop    TextVerticalAlign = "top"
+	AlignMiddle TextVerticalAlign = "middle"
+	AlignBottom TextVerticalAlign = "bottom"
+)
+
+// TextOverflow is how text larger than the label bounds is handled
+type TextOverflow string
+
+const (
+	// OverflowVisible draws text outside of our bounds
+	OverflowVisible TextOverflow = "visible"
+	// OverflowClip cuts off text outside of our bounds, rotation is ignored
+	OverflowClip TextOverflow = "clip"
+	// OverflowEllipsis drops lines that do not fit and
+	// shortens the last line and long lines with an ellipsis
+	OverflowEllipsis TextOverflow = "ellipsis"
+	// OverflowShrink scales the text down until it fits
+	OverflowShrink TextOverflow = "shrink"
+)
+
+// Ellipsis is added to lines cut off by OverflowEllipsis
+const Ellipsis = "..."
 
+// minShrinkScale stops shrinking text that can never fit
+const minShrinkScale = 0.1
+
+// labelLine is a single line of text after wrapping
+type labelLine struct {
+	text  string
+	width float64
+	// justify spreads the words out to fill the line
+	justify bool
+}
+
+// labelLayout is our lines placed inside of a bounds size
+type labelLayout struct {
+	width  float64
+	height float64
+	scale  float64
+	lines  []labelLine
+}
+
+// LabelVisual draws text in a single font, by default the text is one line
+// per newline sized to fit the text. With word wrap on lines wrap to
+// our Transform width, so either set a width or stretch the anchors.
 type LabelVisual struct {
 	*igloo.Visualer
 	ebiten.ColorM
 
-	font    *content.Font
-	text    string
-	isDirty bool
+	font          *content.Font
+	text          string
+	wordWrap      bool
+	align         TextAlign
+	verticalAlign TextVerticalAlign
+	overflow      TextOverflow
+	lineSpacing   float64
+	wrapWidth     float64
+	layout        *labelLayout
+	isDirty       bool
 }
 
 func NewLabelVisual() *LabelVisual {
 	v := &LabelVisual{
-		isDirty: false,
+		align:         AlignLeft,
+		verticalAlign: AlignTop,
+		overflow:      OverflowVisible,
+		isDirty:       false,
 	}
 
 	v.Visualer = &igloo.Visualer{
@@ -45,6 +119,12 @@ func (v *LabelVisual) Clean() {
 	v.isDirty = false
 }
 
+// markDirty updates our native size and drops our last layout
+func (v *LabelVisual) markDirty() {
+	v.layout = nil
+	v.isDirty = true
+}
+
 func (v *LabelVisual) Font() *content.Font {
 	return v.font
 }
@@ -56,38 +136,397 @@ func (v *LabelVisual) SetFont(f *content.Font) {
 
 	v.Transform.SetFixedOffset(f.Ascent())
 	v.font = f
-	v.isDirty = true
+	v.markDirty()
 }
 
 func (v *LabelVisual) Text() string {
 	return v.text
 }
 
+// SetText sets our text, newlines always start a new line
 func (v *LabelVisual) SetText(newText string) {
 	if v.text == newText {
 		return
 	}
 
 	v.text = newText
+	v.markDirty()
+}
+
+// BindText sets our text to the translated key and keeps it updated
+// when the language changes, unbind before disposing of the label.
+func (v *LabelVisual) BindText(loc *locale.Locale, key string, args locale.Args) *locale.Binding {
+	return loc.Bind(key, args, v.SetText)
+}
+
+func (v *LabelVisual) WordWrap() bool {
+	return v.wordWrap
+}
+
+// SetWordWrap breaks lines between words to fit our Transform width
+func (v *LabelVisual) SetWordWrap(wordWrap bool) {
+	if v.wordWrap == wordWrap {
+		return
+	}
+
+	v.wordWrap = wordWrap
+	v.markDirty()
+}
+
+func (v *LabelVisual) Align() TextAlign {
+	return v.align
+}
+
+func (v *LabelVisual) SetAlign(align TextAlign) {
+	if v.align == align {
+		return
+	}
+
+	v.align = align
+	v.isDirty = true
+}
+
+func (v *LabelVisual) VerticalAlign() TextVerticalAlign {
+	return v.verticalAlign
+}
+
+func (v *LabelVisual) SetVerticalAlign(verticalAlign TextVerticalAlign) {
+	if v.verticalAlign == verticalAlign {
+		return
+	}
+
+	v.verticalAlign = verticalAlign
 	v.isDirty = true
 }
 
+func (v *LabelVisual) Overflow() TextOverflow {
+	return v.overflow
+}
+
+func (v *LabelVisual) SetOverflow(overflow TextOverflow) {
+	if v.overflow == overflow {
+		return
+	}
+
+	v.overflow = overflow
+	v.markDirty()
+}
+
+func (v *LabelVisual) LineSpacing() float64 {
+	return v.lineSpacing
+}
+
+// SetLineSpacing sets the extra space between lines, negative values overlap lines
+func (v *LabelVisual) SetLineSpacing(lineSpacing float64) {
+	if v.lineSpacing == lineSpacing {
+		return
+	}
+
+	v.lineSpacing = lineSpacing
+	v.markDirty()
+}
+
+// NativeSize is the size of our text, when wrapping our width is the
+// width of our last bounds, or our Transform width before we are built.
 func (v *LabelVisual) NativeSize() (float64, float64) {
-	rect := text.BoundString(v.font, v.text)
-	w, h := float64(rect.Dx()), float64(rect.Dy())
+	if v.font == nil {
+		return 0, 0
+	}
+
+	width := 0.0
+	if v.wordWrap {
+		width = v.wrapWidth
+		if width <= 0 {
+			width = v.Transform.Width()
+		}
+	}
+
+	lines := v.lines(width)
+
+	for _, line := range lines {
+		width = math.Max(width, line.width)
+	}
+
+	return width, v.linesHeight(len(lines))
+}
+
+func (v *LabelVisual) measure(s string) float64 {
+	return float64(font.MeasureString(v.font, s)) / 64
+}
+
+func (v *LabelVisual) lineAdvance() float64 {
+	return v.font.LineHeight() + v.lineSpacing
+}
+
+func (v *LabelVisual) linesHeight(count int) float64 {
+	if count == 0 {
+		return v.font.LineHeight()
+	}
+
+	return float64(count)*v.lineAdvance() - v.lineSpacing
+}
+
+// lines splits our text into lines, wrapping words to fit
+// a width when word wrap is on and the width is positive.
+func (v *LabelVisual) lines(width float64) []labelLine {
+	var lines []labelLine
+
+	for _, paragraph := range strings.Split(v.text, "\n") {
+		if !v.wordWrap || width <= 0 {
+			lines = append(lines, labelLine{text: paragraph, width: v.measure(paragraph)})
+			continue
+		}
+
+		lines = append(lines, v.wrap(paragraph, width)...)
+	}
+
+	return lines
+}
+
+// wrap breaks a paragraph into lines no wider than width,
+// words wider than the line are kept on a line of their own.
+func (v *LabelVisual) wrap(paragraph string, width float64) []labelLine {
+	words := strings.Fields(paragraph)
+	if len(words) == 0 {
+		return []labelLine{{}}
+	}
+
+	lines := make([]labelLine, 0, 1)
+	current := words[0]
+
+	for _, word := range words[1:] {
+		next := current + " " + word
+		if v.measure(next) <= width {
+			current = next
+			continue
+		}
+
+		lines = append(lines, labelLine{text: current, width: v.measure(current), justify: true})
+		current = word
+	}
+
+	return append(lines, labelLine{text: current, width: v.measure(current)})
+}
+
+// BoundsBuilt wraps our text to our new bounds width, wrapping to a new
+// width changes our native size, and keeps our layout for drawing.
+func (v *LabelVisual) BoundsBuilt(bounds mathf.Bounds) bool {
+	if v.font == nil {
+		return false
+	}
+
+	if v.wordWrap && bounds.Width != v.wrapWidth {
+		v.wrapWidth = bounds.Width
+		return true
+	}
+
+	if v.layout == nil || v.layout.width != bounds.Width || v.layout.height != bounds.Height {
+		v.layout = v.layoutFor(bounds.Width, bounds.Height)
+	}
 
-	if h < v.font.LineHeight() {
-		h = v.font.LineHeight()
+	return false
+}
+
+// layoutFor places our lines inside of a bounds size, applying our overflow
+func (v *LabelVisual) layoutFor(width, height float64) *labelLayout {
+	layout := &labelLayout{
+		width:  width,
+		height: height,
+		scale:  1,
+		lines:  v.lines(width),
 	}
 
-	return w, h
+	switch v.overflow {
+	case OverflowEllipsis:
+		layout.lines = v.ellipsisLines(layout.lines, width, height)
+	case OverflowShrink:
+		v.shrink(layout)
+	}
+
+	return layout
+}
+
+// ellipsisLines drops lines below our height and shortens lines
+// wider than our width, marking the last line when lines were dropped.
+func (v *LabelVisual) ellipsisLines(lines []labelLine, width, height float64) []labelLine {
+	visible := int((height + v.lineSpacing) / v.lineAdvance())
+	visible = mathf.ClampInt(visible, 1, len(lines))
+
+	if visible < len(lines) {
+		lines = lines[:visible]
+		last := &lines[visible-1]
+		last.text = v.ellipsis(last.text+Ellipsis, width)
+	}
+
+	for i := range lines {
+		if lines[i].width > width {
+			lines[i].text = v.ellipsis(lines[i].text, width)
+		}
+
+		lines[i].width = v.measure(lines[i].text)
+	}
+
+	return lines
+}
+
+// ellipsis removes characters from the end of s until it fits
+// into width with an ellipsis, text already fitting is unchanged.
+func (v *LabelVisual) ellipsis(s string, width float64) string {
+	if v.measure(s) <= width {
+		return s
+	}
+
+	s = strings.TrimSuffix(s, Ellipsis)
+	runes := []rune(s)
+
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+
+		shortened := strings.TrimRight(string(runes), " ") + Ellipsis
+		if v.measure(shortened) <= width {
+			return shortened
+		}
+	}
+
+	return Ellipsis
+}
+
+// shrink scales the layout down until all lines fit,
+// wrapped lines are wrapped again to the larger scaled width.
+func (v *LabelVisual) shrink(layout *labelLayout) {
+	for layout.scale > minShrinkScale {
+		fitWidth, fitHeight := 0.0, v.linesHeight(len(layout.lines))*layout.scale
+
+		for _, line := range layout.lines {
+			fitWidth = math.Max(fitWidth, line.width*layout.scale)
+		}
+
+		if fitWidth <= layout.width && fitHeight <= layout.height {
+			return
+		}
+
+		layout.scale = math.Min(layout.scale*0.9, layout.scale*layout.width/fitWidth)
+		layout.lines = v.lines(layout.width / layout.scale)
+	}
 }
 
 func (v *LabelVisual) Draw(dest *ebiten.Image) {
-	text.DrawWithOptions(dest, v.text, v.font, &ebiten.DrawImageOptions{
-		GeoM:          v.Transform.GeoM(),
+	if v.font == nil {
+		return
+	}
+
+	bounds := v.Transform.Bounds()
+	nativeWidth, nativeHeight := v.Transform.NaturalSize()
+
+	if bounds.Width <= 0 || bounds.Height <= 0 || nativeWidth <= 0 || nativeHeight <= 0 {
+		return
+	}
+
+	if v.overflow == OverflowClip {
+		clip := v.Transform.ScreenBounds()
+		dest = dest.SubImage(image.Rect(
+			int(math.Floor(clip.X)),
+			int(math.Floor(clip.Y)),
+			int(math.Ceil(clip.Right())),
+			int(math.Ceil(clip.Bottom())),
+		)).(*ebiten.Image)
+	}
+
+	// draw in our final size by undoing the scale of our transform
+	var base ebiten.GeoM
+
+	base.Scale(nativeWidth/bounds.Width, nativeHeight/bounds.Height)
+	base.Concat(v.Transform.GeoM())
+
+	// our layout is kept when we are built, until then use a new layout
+	layout := v.layout
+	if layout == nil || layout.width != bounds.Width || layout.height != bounds.Height {
+		layout = v.layoutFor(bounds.Width, bounds.Height)
+	}
+
+	v.drawLines(dest, layout, base)
+}
+
+func (v *LabelVisual) drawLines(dest *ebiten.Image, layout *labelLayout, base ebiten.GeoM) {
+	ascent := v.font.Ascent()
+	blockHeight := v.linesHeight(len(layout.lines)) * layout.scale
+	top := 0.0
+
+	switch v.verticalAlign {
+	case AlignMiddle:
+		top = (layout.height - blockHeight) / 2
+	case AlignBottom:
+		top = layout.height - blockHeight
+	}
+
+	opts := &ebiten.DrawImageOptions{
 		ColorM:        v.ColorM,
 		Filter:        v.font.Filter,
 		CompositeMode: v.font.CompositeMode,
-	})
+	}
+
+	for i, line := range layout.lines {
+		// our transform already moves the baseline down by the ascent
+		y := top + (ascent+float64(i)*v.lineAdvance())*layout.scale - ascent
+
+		if v.align == AlignJustify && line.justify {
+			v.drawJustified(dest, line, layout, y, base, opts)
+			continue
+		}
+
+		x := 0.0
+
+		switch v.align {
+		case AlignCenter:
+			x = (layout.width - line.width*layout.scale) / 2
+		case AlignRight:
+			x = layout.width - line.width*layout.scale
+		}
+
+		v.drawText(dest, line.text, x, y, layout.scale, base, opts)
+	}
+}
+
+// drawJustified draws each word with the extra line space split between them
+func (v *LabelVisual) drawJustified(
+	dest *ebiten.Image,
+	line labelLine,
+	layout *labelLayout,
+	y float64,
+	base ebiten.GeoM,
+	opts *ebiten.DrawImageOptions,
+) {
+	words := strings.Fields(line.text)
+	if len(words) < 2 {
+		v.drawText(dest, line.text, 0, y, layout.scale, base, opts)
+		return
+	}
+
+	wordsWidth := 0.0
+	for _, word := range words {
+		wordsWidth += v.measure(word)
+	}
+
+	gap := (layout.width/layout.scale - wordsWidth) / float64(len(words)-1)
+	x := 0.0
+
+	for _, word := range words {
+		v.drawText(dest, word, x*layout.scale, y, layout.scale, base, opts)
+		x += v.measure(word) + gap
+	}
+}
+
+func (v *LabelVisual) drawText(
+	dest *ebiten.Image,
+	s string,
+	x, y, scale float64,
+	base ebiten.GeoM,
+	opts *ebiten.DrawImageOptions,
+) {
+	opts.GeoM.Reset()
+	opts.GeoM.Scale(scale, scale)
+	opts.GeoM.Translate(x, y)
+	opts.GeoM.Concat(base)
+
+	text.DrawWithOptions(dest, s, v.font, opts)
 }
@@ -0,0 +1,132 @@
+package graphics_test
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestLabelVisualNativeSize(t *testing.T) {
+	// every character is 7 pixels wide
+	f := &content.Font{Face: basicfont.Face7x13}
+	lineHeight := f.LineHeight()
+
+	tests := map[string]struct {
+		text        string
+		wordWrap    bool
+		width       float64
+		lineSpacing float64
+		expectedW   float64
+		expectedH   float64
+	}{
+		"single line": {
+			text:      "hello",
+			expectedW: 35,
+			expectedH: lineHeight,
+		},
+		"newlines": {
+			text:      "hi\nthere",
+			expectedW: 35,
+			expectedH: lineHeight * 2,
+		},
+		"line spacing": {
+			text:        "a\nb\nc",
+			lineSpacing: 4,
+			expectedW:   7,
+			expectedH:   lineHeight*3 + 8,
+		},
+		"wraps to width": {
+			text:      "aa bb cc",
+			wordWrap:  true,
+			width:     40,
+			expectedW: 40,
+			expectedH: lineHeight * 2,
+		},
+		"long words overflow": {
+			text:      "a longword",
+			wordWrap:  true,
+			width:     21,
+			expectedW: 56,
+			expectedH: lineHeight * 2,
+		},
+		"wrap without width": {
+			text:      "aa bb cc",
+			wordWrap:  true,
+			expectedW: 56,
+			expectedH: lineHeight,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := graphics.NewLabelVisual()
+			v.SetFont(f)
+			v.SetText(tc.text)
+			v.SetWordWrap(tc.wordWrap)
+			v.SetLineSpacing(tc.lineSpacing)
+			v.Transform.SetWidth(tc.width)
+
+			w, h := v.NativeSize()
+			if w != tc.expectedW || h != tc.expectedH {
+				t.Fatalf("expected: %v, %v, got: %v, %v", tc.expectedW, tc.expectedH, w, h)
+			}
+		})
+	}
+}
+
+func TestLabelVisualWrapsOnFirstLayout(t *testing.T) {
+	f := &content.Font{Face: basicfont.Face7x13}
+
+	root := mathf.NewTransform()
+	root.SetSize(40, 100)
+	root.SetNaturalWidth(40)
+	root.SetNaturalHeight(100)
+	root.Build(nil)
+
+	v := graphics.NewLabelVisual()
+	v.SetFont(f)
+	v.SetText("aa bb cc")
+	v.SetWordWrap(true)
+	v.Transform.SetAnchors(mathf.SidesStretchHorizontal)
+	v.SetVisible(true)
+	v.Layout(root, root)
+
+	bounds := v.Transform.Bounds()
+	if bounds.Width != 40 || bounds.Height != f.LineHeight()*2 {
+		t.Fatalf("expected: 40, %v, got: %v, %v", f.LineHeight()*2, bounds.Width, bounds.Height)
+	}
+
+	if v.IsDirty() {
+		t.Fatal("expected: clean after layout")
+	}
+}
+
+func TestLabelVisualSettersMarkDirty(t *testing.T) {
+	tests := map[string]func(v *graphics.LabelVisual){
+		"align": func(v *graphics.LabelVisual) {
+			v.SetAlign(graphics.AlignRight)
+		},
+		"vertical align": func(v *graphics.LabelVisual) {
+			v.SetVerticalAlign(graphics.AlignBottom)
+		},
+		"overflow": func(v *graphics.LabelVisual) {
+			v.SetOverflow(graphics.OverflowEllipsis)
+		},
+	}
+
+	for name, set := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := graphics.NewLabelVisual()
+			v.Clean()
+			set(v)
+
+			if !v.IsDirty() {
+				t.Fatal("expected: dirty")
+			}
+		})
+	}
+}
@@ -1,130 +1,17 @@
 package graphics
 
-/*
-
 import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
 	"github.com/miniscruff/igloo/mathf"
 )
 
-// NineSlice represents a renderable slicable element in the world.
-type NineSlice struct {
-	Image     *ebiten.Image
-	Transform *mathf.Transform
-	Visible   bool
-
-	// internal rendering values
-	topLeft      *Sprite
-	topCenter    *Sprite
-	topRight     *Sprite
-	middleLeft   *Sprite
-	middleCenter *Sprite
-	middleRight  *Sprite
-	bottomLeft   *Sprite
-	bottomCenter *Sprite
-	bottomRight  *Sprite
-	borders      SliceBorders
-	lastVisible  bool
-}
-
-func (s *NineSlice) positionAndScaleImages() {
-	width := s.Transform.Width()
-	height := s.Transform.Height()
-	anchor := s.Transform.Anchor()
-
-	centerWidth := width - float64(s.borders.Left+s.borders.Right)
-	if centerWidth <= 0 {
-		centerWidth = 0
-	}
-
-	middleHeight := height - float64(s.borders.Top+s.borders.Bottom)
-	if middleHeight <= 0 {
-		middleHeight = 0
-	}
-
-	halfCenterWidth := centerWidth / 2
-	halfMiddleHeight := middleHeight / 2
-	centerX := s.Transform.X() - width*anchor.X + width*0.5
-	middleY := s.Transform.Y() - height*anchor.Y + height*0.5
-
-	// center
-	s.middleCenter.Transform.SetX(centerX)
-	s.middleCenter.Transform.SetY(middleY)
-	s.middleCenter.Transform.SetWidth(centerWidth)
-	s.middleCenter.Transform.SetHeight(middleHeight)
-
-	// corners
-	s.topLeft.Transform.SetX(centerX - halfCenterWidth)
-	s.topLeft.Transform.SetY(middleY - halfMiddleHeight)
-
-	s.topRight.Transform.SetX(centerX + halfCenterWidth)
-	s.topRight.Transform.SetY(middleY - halfMiddleHeight)
-
-	s.bottomLeft.Transform.SetX(centerX - halfCenterWidth)
-	s.bottomLeft.Transform.SetY(middleY + halfMiddleHeight)
-
-	s.bottomRight.Transform.SetX(centerX + halfCenterWidth)
-	s.bottomRight.Transform.SetY(middleY + halfMiddleHeight)
-
-	// edges
-	s.topCenter.Transform.SetX(centerX)
-	s.topCenter.Transform.SetY(middleY - halfMiddleHeight)
-	s.topCenter.Transform.SetWidth(centerWidth)
-
-	s.bottomCenter.Transform.SetX(centerX)
-	s.bottomCenter.Transform.SetY(middleY + halfMiddleHeight)
-	s.bottomCenter.Transform.SetWidth(centerWidth)
-
-	s.middleLeft.Transform.SetX(centerX - halfCenterWidth)
-	s.middleLeft.Transform.SetY(middleY)
-	s.middleLeft.Transform.SetHeight(middleHeight)
-
-	s.middleRight.Transform.SetX(centerX + halfCenterWidth)
-	s.middleRight.Transform.SetY(middleY)
-	s.middleRight.Transform.SetHeight(middleHeight)
-}
-
-// Draw will render the sprite onto the canvas.
-// If our transform, sprite or camera are dirty then we will update internal
-// values accordingly.
-func (s *NineSlice) Draw(dest *ebiten.Image, camera Camera) {
-	turnedOn := s.Visible && !s.lastVisible
-	turnedOff := !s.Visible && s.lastVisible
-
-	if s.Transform.IsDirty() || turnedOn {
-		s.positionAndScaleImages()
-		s.Transform.Clean()
-	}
-
-	if turnedOn || turnedOff {
-		s.topLeft.Visible = s.Visible
-		s.topCenter.Visible = s.Visible
-		s.topRight.Visible = s.Visible
-		s.middleLeft.Visible = s.Visible
-		s.middleCenter.Visible = s.Visible
-		s.middleRight.Visible = s.Visible
-		s.bottomLeft.Visible = s.Visible
-		s.bottomCenter.Visible = s.Visible
-		s.bottomRight.Visible = s.Visible
-	}
-
-	s.topLeft.Draw(dest, camera)
-	s.topCenter.Draw(dest, camera)
-	s.topRight.Draw(dest, camera)
-	s.middleLeft.Draw(dest, camera)
-	s.middleCenter.Draw(dest, camera)
-	s.middleRight.Draw(dest, camera)
-	s.bottomLeft.Draw(dest, camera)
-	s.bottomCenter.Draw(dest, camera)
-	s.bottomRight.Draw(dest, camera)
-
-	s.lastVisible = s.Visible
-}
-
-// SliceBorders organizes the sizes for our nine slice to generate the internal sprites.
+// SliceBorders organizes the sizes for our nine slice to generate the internal regions.
+// A zero Center or Middle uses the rest of the sprite width or height.
 type SliceBorders struct {
 	// Width of the left column
 	Left int
@@ -140,112 +27,285 @@ type SliceBorders struct {
 	Bottom int
 }
 
-func SubImageWidthHeight(img *ebiten.Image, x, y, width, height int) *ebiten.Image {
-	sx := img.Bounds().Min.X
-	sy := img.Bounds().Min.Y
-	rect := image.Rect(sx+x, sy+y, sx+x+width, sy+y+height)
-
-	return img.SubImage(rect).(*ebiten.Image)
-}
+// SliceMode is how edges and the center fill their space
+type SliceMode string
+
+const (
+	// SliceStretch scales edges and the center to fit
+	SliceStretch SliceMode = "stretch"
+	// SliceTile repeats edges and the center, cutting off the last repeat
+	SliceTile SliceMode = "tile"
+)
 
-func (b *SliceBorders) TopLeftSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, 0, 0, b.Left, b.Top),
-		mathf.TransformWithAnchor(mathf.AnchorBottomRight),
-	)
+// NineSliceVisual draws a sprite cut into nine regions, corners keep their
+// size while edges and the center fill the rest of our Transform size.
+// Sprites from atlases that were rotated or trimmed can not be sliced.
+type NineSliceVisual struct {
+	*igloo.Visualer
+
+	sprite      *content.Sprite
+	borders     SliceBorders
+	mode        SliceMode
+	hollow      bool
+	cornersOnly bool
+	regions     [9]*ebiten.Image
+	isDirty     bool
 }
 
-func (b *SliceBorders) TopCenterSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left, 0, b.Center, b.Top),
-		mathf.TransformWithAnchor(mathf.AnchorBottomCenter),
-	)
+func NewNineSliceVisual() *NineSliceVisual {
+	v := &NineSliceVisual{
+		mode:    SliceStretch,
+		isDirty: true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
 }
 
-func (b *SliceBorders) TopRightSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left+b.Center, 0, b.Right, b.Top),
-		mathf.TransformWithAnchor(mathf.AnchorBottomLeft),
-	)
+func (v *NineSliceVisual) Sprite() *content.Sprite {
+	return v.sprite
 }
 
-func (b *SliceBorders) MiddleLeftSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, 0, b.Top, b.Left, b.Middle),
-		mathf.TransformWithAnchor(mathf.AnchorMiddleRight),
-	)
+func (v *NineSliceVisual) SetSprite(sprite *content.Sprite) {
+	if v.sprite == sprite {
+		return
+	}
+
+	v.sprite = sprite
+	v.slice()
+	v.isDirty = true
 }
 
-func (b *SliceBorders) MiddleCenterSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left, b.Top, b.Center, b.Middle),
-		mathf.TransformWithAnchor(mathf.AnchorMiddleCenter),
-	)
+func (v *NineSliceVisual) Borders() SliceBorders {
+	return v.borders
 }
 
-func (b *SliceBorders) MiddleRightSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left+b.Center, b.Top, b.Right, b.Middle),
-		mathf.TransformWithAnchor(mathf.AnchorMiddleLeft),
-	)
+func (v *NineSliceVisual) SetBorders(borders SliceBorders) {
+	if v.borders == borders {
+		return
+	}
+
+	v.borders = borders
+	v.slice()
+	v.isDirty = true
 }
 
-func (b *SliceBorders) BottomLeftSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, 0, b.Top+b.Middle, b.Left, b.Bottom),
-		mathf.TransformWithAnchor(mathf.AnchorTopRight),
-	)
+func (v *NineSliceVisual) Mode() SliceMode {
+	return v.mode
 }
 
-func (b *SliceBorders) BottomCenterSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left, b.Top+b.Middle, b.Center, b.Bottom),
-		mathf.TransformWithAnchor(mathf.AnchorTopCenter),
-	)
+func (v *NineSliceVisual) SetMode(mode SliceMode) {
+	v.mode = mode
 }
 
-func (b *SliceBorders) BottomRightSprite(img *ebiten.Image) *Sprite {
-	return NewSprite(
-		SubImageWidthHeight(img, b.Left+b.Center, b.Top+b.Middle, b.Right, b.Bottom),
-		mathf.TransformWithAnchor(mathf.AnchorTopLeft),
-	)
+// Hollow returns whether or not the center is skipped
+func (v *NineSliceVisual) Hollow() bool {
+	return v.hollow
 }
 
-// NewNineSlice will create a nine slice from an image and border values
-// Defaults include:
-// * Width and height of the image
-// * Position at 0,0
-// * Rotation of 0
-// * Anchor in the middle center
-func NewNineSlice(
-	image *ebiten.Image,
-	borders SliceBorders,
-	options ...mathf.TransformOption,
-) *NineSlice {
-	w, h := image.Size()
-	// prepend our natural size option
-	options = append([]mathf.TransformOption{
-		mathf.TransformWithNaturalSize(float64(w), float64(h)),
-	}, options...)
-	transform := mathf.NewTransform(options...)
+// SetHollow skips drawing the center, for frames and outlines
+func (v *NineSliceVisual) SetHollow(hollow bool) {
+	v.hollow = hollow
+}
+
+// CornersOnly returns whether or not only the corners are drawn
+func (v *NineSliceVisual) CornersOnly() bool {
+	return v.cornersOnly
+}
+
+// SetCornersOnly only draws the four corners, for brackets and
+// selection markers that leave the edges empty.
+func (v *NineSliceVisual) SetCornersOnly(cornersOnly bool) {
+	v.cornersOnly = cornersOnly
+}
 
-	nineSlice := &NineSlice{
-		Image:        image,
-		Transform:    transform,
-		Visible:      true,
-		borders:      borders,
-		topLeft:      borders.TopLeftSprite(image),
-		topCenter:    borders.TopCenterSprite(image),
-		topRight:     borders.TopRightSprite(image),
-		middleLeft:   borders.MiddleLeftSprite(image),
-		middleCenter: borders.MiddleCenterSprite(image),
-		middleRight:  borders.MiddleRightSprite(image),
-		bottomLeft:   borders.BottomLeftSprite(image),
-		bottomCenter: borders.BottomCenterSprite(image),
-		bottomRight:  borders.BottomRightSprite(image),
-		lastVisible:  true,
-	}
-
-	return nineSlice
-}
-*/
+func (v *NineSliceVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *NineSliceVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *NineSliceVisual) NativeSize() (float64, float64) {
+	if v.sprite == nil {
+		return 0, 0
+	}
+
+	size := v.sprite.Image.Bounds().Size()
+
+	return float64(size.X), float64(size.Y)
+}
+
+// slice cuts our sprite into regions for each column and row
+func (v *NineSliceVisual) slice() {
+	v.regions = [9]*ebiten.Image{}
+
+	if v.sprite == nil {
+		return
+	}
+
+	bounds := v.sprite.Image.Bounds()
+	columns := sliceSizes(bounds.Dx(), v.borders.Left, v.borders.Center, v.borders.Right)
+	rows := sliceSizes(bounds.Dy(), v.borders.Top, v.borders.Middle, v.borders.Bottom)
+	y := bounds.Min.Y
+
+	for row, height := range rows {
+		x := bounds.Min.X
+
+		for column, width := range columns {
+			if width > 0 && height > 0 {
+				rect := image.Rect(x, y, x+width, y+height)
+				v.regions[row*3+column] = v.sprite.Image.SubImage(rect).(*ebiten.Image)
+			}
+
+			x += width
+		}
+
+		y += height
+	}
+}
+
+func (v *NineSliceVisual) Draw(dest *ebiten.Image) {
+	if v.sprite == nil {
+		return
+	}
+
+	bounds := v.Transform.Bounds()
+	nativeWidth, nativeHeight := v.Transform.NaturalSize()
+
+	if bounds.Width <= 0 || bounds.Height <= 0 || nativeWidth <= 0 || nativeHeight <= 0 {
+		return
+	}
+
+	// draw in our final size by undoing the scale of our transform
+	var base ebiten.GeoM
+
+	base.Scale(nativeWidth/bounds.Width, nativeHeight/bounds.Height)
+	base.Concat(v.Transform.GeoM())
+
+	left, right := sliceBorderSizes(bounds.Width, v.borders.Left, v.borders.Right)
+	top, bottom := sliceBorderSizes(bounds.Height, v.borders.Top, v.borders.Bottom)
+	columns := [3]float64{0, left, bounds.Width - right}
+	widths := [3]float64{left, bounds.Width - left - right, right}
+	rows := [3]float64{0, top, bounds.Height - bottom}
+	heights := [3]float64{top, bounds.Height - top - bottom, bottom}
+
+	opts := &ebiten.DrawImageOptions{
+		ColorM:        v.sprite.ColorM,
+		Filter:        v.sprite.Filter,
+		CompositeMode: v.sprite.CompositeMode,
+	}
+
+	for i, region := range v.regions {
+		row, column := i/3, i%3
+		if region == nil || !v.drawsRegion(row, column) {
+			continue
+		}
+
+		rect := mathf.NewBoundsWidthHeight(columns[column], rows[row], widths[column], heights[row])
+		isCorner := row != 1 && column != 1
+
+		if v.mode == SliceTile && !isCorner {
+			tileRegion(dest, region, rect, base, opts)
+		} else {
+			stretchRegion(dest, region, rect, base, opts)
+		}
+	}
+}
+
+func (v *NineSliceVisual) drawsRegion(row, column int) bool {
+	switch {
+	case row != 1 && column != 1:
+		return true
+	case row == 1 && column == 1:
+		return !v.hollow && !v.cornersOnly
+	default:
+		return !v.cornersOnly
+	}
+}
+
+func stretchRegion(
+	dest, region *ebiten.Image,
+	rect mathf.Bounds,
+	base ebiten.GeoM,
+	opts *ebiten.DrawImageOptions,
+) {
+	if rect.Width <= 0 || rect.Height <= 0 {
+		return
+	}
+
+	size := region.Bounds().Size()
+
+	opts.GeoM.Reset()
+	opts.GeoM.Scale(rect.Width/float64(size.X), rect.Height/float64(size.Y))
+	opts.GeoM.Translate(rect.X, rect.Y)
+	opts.GeoM.Concat(base)
+
+	dest.DrawImage(region, opts)
+}
+
+func tileRegion(
+	dest, region *ebiten.Image,
+	rect mathf.Bounds,
+	base ebiten.GeoM,
+	opts *ebiten.DrawImageOptions,
+) {
+	bounds := region.Bounds()
+	size := bounds.Size()
+
+	for y := 0.0; y < rect.Height; y += float64(size.Y) {
+		for x := 0.0; x < rect.Width; x += float64(size.X) {
+			// the last tile in each direction is cut to fit
+			width := mathf.ClampInt(int(rect.Width-x), 0, size.X)
+			height := mathf.ClampInt(int(rect.Height-y), 0, size.Y)
+
+			if width <= 0 || height <= 0 {
+				continue
+			}
+
+			part := region.SubImage(image.Rect(
+				bounds.Min.X,
+				bounds.Min.Y,
+				bounds.Min.X+width,
+				bounds.Min.Y+height,
+			)).(*ebiten.Image)
+
+			opts.GeoM.Reset()
+			opts.GeoM.Translate(rect.X+x, rect.Y+y)
+			opts.GeoM.Concat(base)
+
+			dest.DrawImage(part, opts)
+		}
+	}
+}
+
+// sliceSizes returns the size of each column or row, the center
+// uses the rest of the total when zero.
+func sliceSizes(total, start, center, end int) [3]int {
+	if center == 0 {
+		center = total - start - end
+	}
+
+	return [3]int{start, center, end}
+}
+
+// sliceBorderSizes shrinks both borders evenly when there is not enough room
+func sliceBorderSizes(total float64, start, end int) (float64, float64) {
+	startSize := float64(start)
+	endSize := float64(end)
+
+	if startSize+endSize > total && startSize+endSize > 0 {
+		scale := total / (startSize + endSize)
+		return startSize * scale, endSize * scale
+	}
+
+	return startSize, endSize
+}
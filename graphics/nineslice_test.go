@@ -0,0 +1,145 @@
+package graphics_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// newGridSprite creates a sprite with a pixel for each value of a grid,
+// values are used as the red of each pixel.
+func newGridSprite(grid [][]uint8) *content.Sprite {
+	img := image.NewNRGBA(image.Rect(0, 0, len(grid[0]), len(grid)))
+
+	for y, row := range grid {
+		for x, value := range row {
+			img.Set(x, y, color.NRGBA{R: value, A: 0xff})
+		}
+	}
+
+	return &content.Sprite{Image: ebiten.NewImageFromImage(img)}
+}
+
+func TestNineSliceVisualDraw(t *testing.T) {
+	threeByThree := [][]uint8{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	ones := graphics.SliceBorders{Left: 1, Right: 1, Top: 1, Bottom: 1}
+
+	tests := map[string]struct {
+		grid        [][]uint8
+		borders     graphics.SliceBorders
+		mode        graphics.SliceMode
+		hollow      bool
+		cornersOnly bool
+		width       float64
+		height      float64
+		// red values at each point, zero expects a transparent pixel
+		expected map[image.Point]uint8
+	}{
+		"stretch": {
+			grid:    threeByThree,
+			borders: ones,
+			width:   9,
+			height:  9,
+			expected: map[image.Point]uint8{
+				{0, 0}: 1, {4, 0}: 2, {8, 0}: 3,
+				{0, 4}: 4, {4, 4}: 5, {8, 4}: 6,
+				{0, 8}: 7, {4, 8}: 8, {8, 8}: 9,
+			},
+		},
+		"hollow": {
+			grid:     threeByThree,
+			borders:  ones,
+			hollow:   true,
+			width:    9,
+			height:   9,
+			expected: map[image.Point]uint8{{0, 0}: 1, {4, 0}: 2, {4, 4}: 0},
+		},
+		"corners only": {
+			grid:        threeByThree,
+			borders:     ones,
+			cornersOnly: true,
+			width:       9,
+			height:      9,
+			expected:    map[image.Point]uint8{{0, 0}: 1, {4, 0}: 0, {4, 4}: 0},
+		},
+		"borders larger than the target": {
+			grid: [][]uint8{
+				{1, 1, 3, 3},
+				{1, 1, 3, 3},
+				{7, 7, 9, 9},
+				{7, 7, 9, 9},
+			},
+			borders:  graphics.SliceBorders{Left: 2, Right: 2, Top: 2, Bottom: 2},
+			width:    2,
+			height:   2,
+			expected: map[image.Point]uint8{{0, 0}: 1, {1, 0}: 3, {0, 1}: 7, {1, 1}: 9},
+		},
+		"stretch wide center": {
+			grid: [][]uint8{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+				{9, 10, 11, 12},
+			},
+			borders:  ones,
+			width:    6,
+			height:   3,
+			expected: map[image.Point]uint8{{1, 1}: 6, {2, 1}: 6, {3, 1}: 7, {3, 0}: 3},
+		},
+		"tile wide center": {
+			grid: [][]uint8{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+				{9, 10, 11, 12},
+			},
+			borders:  ones,
+			mode:     graphics.SliceTile,
+			width:    6,
+			height:   3,
+			expected: map[image.Point]uint8{{1, 1}: 6, {2, 1}: 7, {3, 1}: 6, {3, 0}: 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := mathf.NewTransform()
+			root.SetSize(64, 64)
+			root.SetNaturalWidth(64)
+			root.SetNaturalHeight(64)
+			root.Build(nil)
+
+			v := graphics.NewNineSliceVisual()
+			v.SetSprite(newGridSprite(tc.grid))
+			v.SetBorders(tc.borders)
+			v.SetHollow(tc.hollow)
+			v.SetCornersOnly(tc.cornersOnly)
+			v.Transform.SetSize(tc.width, tc.height)
+			v.SetVisible(true)
+
+			if tc.mode != "" {
+				v.SetMode(tc.mode)
+			}
+
+			v.Layout(root, root)
+
+			dest := ebiten.NewImage(64, 64)
+			v.Visualer.Draw(dest)
+
+			for point, red := range tc.expected {
+				got := pixelAt(t, dest, point.X, point.Y)
+				if got.R != red || (got.A == 0) != (red == 0) {
+					t.Fatalf("expected %v: %v, got: %v", point, red, got)
+				}
+			}
+		})
+	}
+}
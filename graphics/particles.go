@@ -0,0 +1,346 @@
+package graphics
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// particle is a single live particle, positions are local to the emitter
+// unless the emitter is in world space.
+type particle struct {
+	position        mathf.Vec2
+	velocity        mathf.Vec2
+	rotation        float64
+	angularVelocity float64
+	age             float64
+	lifetime        float64
+}
+
+// ParticleEmitterVisual spawns and draws particles from a definition,
+// all particles are drawn in a single batch using the definition sprite.
+// Sprites from atlases that were rotated or trimmed are drawn untrimmed.
+// Add the emitter to a ticker to simulate particles.
+type ParticleEmitterVisual struct {
+	*igloo.Visualer
+
+	def        *content.ParticleEmitterDef
+	particles  []particle
+	random     *rand.Rand
+	elapsed    float64
+	spawnDebt  float64
+	nextBurst  int
+	isEmitting bool
+	isPaused   bool
+	vertices   []ebiten.Vertex
+	indices    []uint16
+	isDirty    bool
+}
+
+func NewParticleEmitterVisual(def *content.ParticleEmitterDef) *ParticleEmitterVisual {
+	v := &ParticleEmitterVisual{
+		def:        def,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		isEmitting: true,
+		isDirty:    true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+func (v *ParticleEmitterVisual) Def() *content.ParticleEmitterDef {
+	return v.def
+}
+
+// SetDef replaces our definition and restarts emitting,
+// live particles are kept.
+func (v *ParticleEmitterVisual) SetDef(def *content.ParticleEmitterDef) {
+	v.def = def
+	v.Restart()
+	v.isDirty = true
+}
+
+// SetSeed makes spawning repeatable
+func (v *ParticleEmitterVisual) SetSeed(seed int64) {
+	v.random.Seed(seed)
+}
+
+// Count returns how many particles are alive
+func (v *ParticleEmitterVisual) Count() int {
+	return len(v.particles)
+}
+
+// IsEmitting returns whether or not we are spawning new particles
+func (v *ParticleEmitterVisual) IsEmitting() bool {
+	return v.isEmitting
+}
+
+// Restart starts emitting from the beginning including bursts
+func (v *ParticleEmitterVisual) Restart() {
+	v.elapsed = 0
+	v.spawnDebt = 0
+	v.nextBurst = 0
+	v.isEmitting = true
+}
+
+// Stop spawning new particles, live particles continue until they expire
+func (v *ParticleEmitterVisual) Stop() {
+	v.isEmitting = false
+}
+
+// Clear removes all live particles
+func (v *ParticleEmitterVisual) Clear() {
+	v.particles = v.particles[:0]
+}
+
+// Burst spawns count particles right away
+func (v *ParticleEmitterVisual) Burst(count int) {
+	limit := v.def.ParticleLimit()
+
+	for i := 0; i < count && len(v.particles) < limit; i++ {
+		v.particles = append(v.particles, v.spawn())
+	}
+}
+
+func (v *ParticleEmitterVisual) Resume() {
+	v.isPaused = false
+}
+
+func (v *ParticleEmitterVisual) Pause() {
+	v.isPaused = true
+}
+
+func (v *ParticleEmitterVisual) IsPaused() bool {
+	return v.isPaused
+}
+
+func (v *ParticleEmitterVisual) Tick() {
+	if v.def == nil || v.isPaused {
+		return
+	}
+
+	delta := mathf.TickDelta()
+
+	v.update(delta)
+
+	if v.isEmitting {
+		v.emit(delta)
+	}
+}
+
+// update ages and moves particles removing any that expired
+func (v *ParticleEmitterVisual) update(delta float64) {
+	drag := math.Max(0, 1-v.def.Drag*delta)
+
+	// keep particles in the order they spawned so newer ones draw on top
+	alive := v.particles[:0]
+
+	for i := range v.particles {
+		p := v.particles[i]
+		p.age += delta
+
+		if p.age >= p.lifetime {
+			continue
+		}
+
+		p.velocity.X = (p.velocity.X + v.def.Gravity.X*delta) * drag
+		p.velocity.Y = (p.velocity.Y + v.def.Gravity.Y*delta) * drag
+		p.position.X += p.velocity.X * delta
+		p.position.Y += p.velocity.Y * delta
+		p.rotation += p.angularVelocity * delta
+		alive = append(alive, p)
+	}
+
+	v.particles = alive
+}
+
+// emit spawns particles from our rate and bursts
+func (v *ParticleEmitterVisual) emit(delta float64) {
+	v.elapsed += delta
+	v.spawnDebt += v.def.Rate * delta
+
+	count := int(v.spawnDebt)
+	v.spawnDebt -= float64(count)
+
+	for v.nextBurst < len(v.def.Bursts) && v.def.Bursts[v.nextBurst].Time <= v.elapsed {
+		count += v.def.Bursts[v.nextBurst].Count
+		v.nextBurst++
+	}
+
+	v.Burst(count)
+
+	if v.def.Duration <= 0 || v.elapsed < v.def.Duration {
+		return
+	}
+
+	if v.def.Loop {
+		v.elapsed -= v.def.Duration
+		v.nextBurst = 0
+	} else {
+		v.isEmitting = false
+	}
+}
+
+func (v *ParticleEmitterVisual) randomRange(r content.FloatRange) float64 {
+	return r.Lerp(v.random.Float64())
+}
+
+// spawnPoint returns a random point in our emitter shape
+func (v *ParticleEmitterVisual) spawnPoint() mathf.Vec2 {
+	w, h := v.def.Width, v.def.Height
+
+	switch v.def.Shape {
+	case content.EmitLine:
+		return mathf.Vec2{X: v.random.Float64() * w, Y: h / 2}
+	case content.EmitRect:
+		return mathf.Vec2{X: v.random.Float64() * w, Y: v.random.Float64() * h}
+	case content.EmitCircle:
+		// square root keeps points evenly spread instead of bunched in the center
+		dist := math.Sqrt(v.random.Float64())
+		angle := v.random.Float64() * 2 * math.Pi
+
+		return mathf.Vec2{
+			X: w/2 + math.Cos(angle)*dist*w/2,
+			Y: h/2 + math.Sin(angle)*dist*h/2,
+		}
+	default:
+		return mathf.Vec2{X: w / 2, Y: h / 2}
+	}
+}
+
+func (v *ParticleEmitterVisual) spawn() particle {
+	position := v.spawnPoint()
+	direction := v.randomRange(v.def.Direction)
+
+	if v.def.WorldSpace {
+		geom := v.Transform.WorldGeoM()
+		position.X, position.Y = geom.Apply(position.X, position.Y)
+
+		// rotate our direction by every parent including any flips
+		x, y := geom.Apply(math.Cos(direction), math.Sin(direction))
+		originX, originY := geom.Apply(0, 0)
+		direction = math.Atan2(y-originY, x-originX)
+	}
+
+	speed := v.randomRange(v.def.Speed)
+
+	return particle{
+		position: position,
+		velocity: mathf.Vec2{
+			X: math.Cos(direction) * speed,
+			Y: math.Sin(direction) * speed,
+		},
+		rotation:        v.randomRange(v.def.Rotation),
+		angularVelocity: v.randomRange(v.def.AngularVelocity),
+		lifetime:        v.randomRange(v.def.Lifetime),
+	}
+}
+
+func (v *ParticleEmitterVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *ParticleEmitterVisual) Clean() {
+	v.isDirty = false
+}
+
+// NativeSize is the size of our emitter shape
+func (v *ParticleEmitterVisual) NativeSize() (float64, float64) {
+	if v.def == nil {
+		return 0, 0
+	}
+
+	return v.def.Width, v.def.Height
+}
+
+func (v *ParticleEmitterVisual) Draw(dest *ebiten.Image) {
+	if v.def == nil || v.def.Sprite == nil || len(v.particles) == 0 {
+		return
+	}
+
+	// world space particles only need our view
+	geom := v.Transform.View()
+	if !v.def.WorldSpace {
+		geom = v.Transform.GeoM()
+	}
+
+	v.vertices = v.vertices[:0]
+	v.indices = v.indices[:0]
+
+	for i := range v.particles {
+		v.appendParticle(&v.particles[i], geom)
+	}
+
+	dest.DrawTriangles(v.vertices, v.indices, v.def.Sprite.Image, &ebiten.DrawTrianglesOptions{
+		ColorM:        v.def.Sprite.ColorM,
+		CompositeMode: v.def.Sprite.CompositeMode,
+		Filter:        v.def.Sprite.Filter,
+	})
+}
+
+// appendParticle adds the quad of a particle centered on its position
+func (v *ParticleEmitterVisual) appendParticle(p *particle, geom ebiten.GeoM) {
+	percent := mathf.Clamp(p.age/p.lifetime, 0, 1)
+	src := v.def.Sprite.Image.Bounds()
+	size := 1.0
+	rotation := p.rotation
+	clr := [4]float64{1, 1, 1, 1}
+
+	if v.def.RotationCurve != nil {
+		rotation += v.def.RotationCurve.At(percent)
+	}
+
+	if v.def.Size != nil {
+		size = v.def.Size.At(percent)
+	}
+
+	if v.def.Color != nil {
+		clr = v.def.Color.At(percent)
+	}
+
+	halfW := float64(src.Dx()) * size / 2
+	halfH := float64(src.Dy()) * size / 2
+	sin, cos := math.Sincos(rotation)
+	start := uint16(len(v.vertices))
+
+	corners := [4]struct{ x, y, srcX, srcY float64 }{
+		{-halfW, -halfH, float64(src.Min.X), float64(src.Min.Y)},
+		{halfW, -halfH, float64(src.Max.X), float64(src.Min.Y)},
+		{-halfW, halfH, float64(src.Min.X), float64(src.Max.Y)},
+		{halfW, halfH, float64(src.Max.X), float64(src.Max.Y)},
+	}
+
+	for _, c := range corners {
+		x, y := geom.Apply(
+			p.position.X+c.x*cos-c.y*sin,
+			p.position.Y+c.x*sin+c.y*cos,
+		)
+
+		v.vertices = append(v.vertices, ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(c.srcX),
+			SrcY:   float32(c.srcY),
+			ColorR: float32(clr[0]),
+			ColorG: float32(clr[1]),
+			ColorB: float32(clr[2]),
+			ColorA: float32(clr[3]),
+		})
+	}
+
+	v.indices = append(v.indices, start, start+1, start+2, start+1, start+3, start+2)
+}
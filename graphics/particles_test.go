@@ -0,0 +1,227 @@
+package graphics_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestParticleEmitterVisual(t *testing.T) {
+	tests := map[string]struct {
+		def      content.ParticleEmitterDef
+		ticks    int
+		expected int
+		emitting bool
+	}{
+		"rate": {
+			def: content.ParticleEmitterDef{
+				Rate:     float64(ebiten.TPS()),
+				Lifetime: content.FloatRange{Min: 10, Max: 10},
+			},
+			ticks:    10,
+			expected: 10,
+			emitting: true,
+		},
+		"bursts once": {
+			def: content.ParticleEmitterDef{
+				Bursts:   []content.ParticleBurst{{Time: 0, Count: 5}},
+				Duration: 0.01,
+				Lifetime: content.FloatRange{Min: 10, Max: 10},
+			},
+			ticks:    10,
+			expected: 5,
+		},
+		"limited": {
+			def: content.ParticleEmitterDef{
+				Bursts:       []content.ParticleBurst{{Time: 0, Count: 50}},
+				MaxParticles: 20,
+				Lifetime:     content.FloatRange{Min: 10, Max: 10},
+			},
+			ticks:    1,
+			expected: 20,
+			emitting: true,
+		},
+		"expired": {
+			def: content.ParticleEmitterDef{
+				Bursts:   []content.ParticleBurst{{Time: 0, Count: 5}},
+				Duration: 0.01,
+				Lifetime: content.FloatRange{Min: 0.05, Max: 0.05},
+			},
+			ticks:    10,
+			expected: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := tc.def
+			if err := def.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			def.Sprite = &content.Sprite{Image: ebiten.NewImage(2, 2)}
+
+			v := graphics.NewParticleEmitterVisual(&def)
+			v.SetSeed(1)
+
+			for i := 0; i < tc.ticks; i++ {
+				v.Tick()
+			}
+
+			if v.Count() != tc.expected || v.IsEmitting() != tc.emitting {
+				t.Fatalf(
+					"expected: %v, %v, got: %v, %v",
+					tc.expected, tc.emitting, v.Count(), v.IsEmitting(),
+				)
+			}
+
+			v.Layout(v.Transform, nil)
+			v.Draw(ebiten.NewImage(8, 8))
+		})
+	}
+}
+
+// newParticleDef creates a still particle def drawing a white square
+func newParticleDef(t *testing.T, lifetime float64) *content.ParticleEmitterDef {
+	sprite := ebiten.NewImage(4, 4)
+	sprite.Fill(color.White)
+
+	def := &content.ParticleEmitterDef{
+		Lifetime: content.FloatRange{Min: lifetime, Max: lifetime},
+		Color: &content.ParticleColorCurve{
+			Start: [4]float64{1, 0, 0, 1},
+			End:   [4]float64{0, 0, 1, 1},
+		},
+	}
+
+	if err := def.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	def.Sprite = &content.Sprite{Image: sprite}
+
+	return def
+}
+
+func newParticleRoot() *mathf.Transform {
+	root := mathf.NewTransform()
+	root.SetSize(64, 64)
+	root.SetNaturalWidth(64)
+	root.SetNaturalHeight(64)
+	root.Build(nil)
+
+	return root
+}
+
+func TestParticleEmitterVisualKeepsSpawnOrder(t *testing.T) {
+	v := graphics.NewParticleEmitterVisual(newParticleDef(t, 0.05))
+	v.Transform.SetPosition(mathf.Vec2{X: 8, Y: 8})
+	v.SetVisible(true)
+	v.Burst(1)
+
+	// the first particle expires long before the other two
+	v.SetDef(newParticleDef(t, 0.5))
+	v.Burst(1)
+	v.SetDef(newParticleDef(t, 10))
+	v.Burst(1)
+
+	for i := 0; i < 5; i++ {
+		v.Tick()
+	}
+
+	if v.Count() != 2 {
+		t.Fatalf("expected: 2 particles, got: %v", v.Count())
+	}
+
+	v.Layout(newParticleRoot(), nil)
+
+	dest := ebiten.NewImage(64, 64)
+	v.Draw(dest)
+
+	// our newest particle has barely aged so it is still red
+	if clr := pixelAt(t, dest, 8, 8); clr.R < 0xf5 {
+		t.Fatalf("expected: newest particle on top, got: %v", clr)
+	}
+}
+
+func TestParticleEmitterVisualWorldDirection(t *testing.T) {
+	root := newParticleRoot()
+
+	// our parent is turned around so moving right moves left on screen
+	parent := graphics.NewEmptyVisual()
+	parent.Transform.SetPosition(mathf.Vec2{X: 32, Y: 32})
+	parent.Transform.SetRotation(math.Pi)
+	parent.SetVisible(true)
+
+	def := newParticleDef(t, 10)
+	def.WorldSpace = true
+	def.Speed = content.FloatRange{Min: 120, Max: 120}
+
+	v := graphics.NewParticleEmitterVisual(def)
+	v.SetVisible(true)
+	parent.InsertChild(v.Visualer)
+	parent.Layout(root, root)
+	v.Burst(1)
+
+	// 20 pixels at 120 pixels per second
+	for i := 0; i < ebiten.TPS()/6; i++ {
+		v.Tick()
+	}
+
+	dest := ebiten.NewImage(64, 64)
+	v.Draw(dest)
+
+	geom := v.Transform.WorldGeoM()
+	x, y := geom.Apply(0, 0)
+
+	if clr := pixelAt(t, dest, int(x)-20, int(y)); clr.A == 0 {
+		t.Fatalf("expected: particle moved left, got: %v", clr)
+	}
+
+	if clr := pixelAt(t, dest, int(x)+20, int(y)); clr.A != 0 {
+		t.Fatalf("expected: nothing to the right, got: %v", clr)
+	}
+}
+
+func TestParticleEmitterVisualRotationCurve(t *testing.T) {
+	sprite := ebiten.NewImage(8, 2)
+	sprite.Fill(color.White)
+
+	// a quarter turn over the whole lifetime turns our wide sprite tall
+	def := &content.ParticleEmitterDef{
+		Lifetime: content.FloatRange{Min: 10, Max: 10},
+		RotationCurve: &content.ParticleCurve{
+			Start: math.Pi / 2,
+			End:   math.Pi / 2,
+		},
+	}
+
+	if err := def.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	def.Sprite = &content.Sprite{Image: sprite}
+
+	v := graphics.NewParticleEmitterVisual(def)
+	v.Transform.SetPosition(mathf.Vec2{X: 8, Y: 8})
+	v.SetVisible(true)
+	v.Burst(1)
+	v.Layout(newParticleRoot(), nil)
+
+	dest := ebiten.NewImage(64, 64)
+	v.Draw(dest)
+
+	if clr := pixelAt(t, dest, 8, 5); clr.A == 0 {
+		t.Fatalf("expected: particle turned tall, got: %v", clr)
+	}
+
+	if clr := pixelAt(t, dest, 5, 8); clr.A != 0 {
+		t.Fatalf("expected: nothing to the side, got: %v", clr)
+	}
+}
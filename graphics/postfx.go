@@ -0,0 +1,390 @@
+package graphics
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+)
+
+// Built in post process shaders use pixel units, positions and texture
+// coordinates are both in pixels.
+const (
+	vignetteShaderSrc = `//kage:unit pixels
+
+package main
+
+var Intensity float
+var Radius float
+var Softness float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	clr := imageSrc0At(texCoord)
+	pos := (texCoord - imageSrc0Origin()) / imageSrc0Size()
+
+	// zero in the center and one in the corners
+	dist := distance(pos, vec2(0.5)) * 1.41421356
+	shade := smoothstep(Radius, Radius+Softness, dist) * Intensity
+
+	return vec4(clr.rgb*(1-shade), clr.a)
+}
+`
+
+	scanlineShaderSrc = `//kage:unit pixels
+
+package main
+
+var Intensity float
+var LineHeight float
+var Curvature float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin := imageSrc0Origin()
+	size := imageSrc0Size()
+
+	// bend the edges away like a curved screen
+	pos := (texCoord-origin)/size*2 - 1
+	pos *= 1 + pos.yx*pos.yx*Curvature
+	pos = pos/2 + 0.5
+
+	if pos.x < 0 || pos.x > 1 || pos.y < 0 || pos.y > 1 {
+		return vec4(0)
+	}
+
+	clr := imageSrc0At(pos*size + origin)
+	line := (position.y - imageDstOrigin().y) / max(LineHeight, 1)
+	shade := (0.5 - 0.5*cos(line*2*3.14159265)) * Intensity
+
+	return vec4(clr.rgb*(1-shade), clr.a)
+}
+`
+
+	aberrationShaderSrc = `//kage:unit pixels
+
+package main
+
+var Offset float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	// split channels further apart towards the edges
+	dir := ((texCoord-imageSrc0Origin())/imageSrc0Size() - 0.5) * 2 * Offset
+
+	red := imageSrc0At(texCoord + dir)
+	green := imageSrc0At(texCoord)
+	blue := imageSrc0At(texCoord - dir)
+
+	return vec4(red.r, green.g, blue.b, max(green.a, max(red.a, blue.a)))
+}
+`
+
+	colorGradeShaderSrc = `//kage:unit pixels
+
+package main
+
+var LutSize float
+var Intensity float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	clr := imageSrc0At(texCoord)
+	if clr.a == 0 {
+		return clr
+	}
+
+	rgb := clr.rgb / clr.a
+	cells := LutSize - 1
+	blue := rgb.b * cells
+	slice0 := floor(blue)
+	slice1 := min(slice0+1, cells)
+	cell := rgb.rg*cells + 0.5
+	origin := imageSrc1Origin()
+
+	low := imageSrc1At(origin + vec2(slice0*LutSize+cell.x, cell.y)).rgb
+	high := imageSrc1At(origin + vec2(slice1*LutSize+cell.x, cell.y)).rgb
+	graded := mix(low, high, blue-slice0)
+
+	return vec4(mix(rgb, graded, Intensity)*clr.a, clr.a)
+}
+`
+
+	bloomThresholdShaderSrc = `//kage:unit pixels
+
+package main
+
+var Threshold float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	clr := imageSrc0At(texCoord)
+	brightness := dot(clr.rgb, vec3(0.2126, 0.7152, 0.0722))
+
+	// only keep the part of our color above the threshold
+	return clr * max(brightness-Threshold, 0) / max(brightness, 0.0001)
+}
+`
+
+	bloomBlurShaderSrc = `//kage:unit pixels
+
+package main
+
+var Direction vec2
+var Amount float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	sum := imageSrc0At(texCoord) * 0.227027
+	sum += (imageSrc0At(texCoord+Direction) + imageSrc0At(texCoord-Direction)) * 0.1945946
+	sum += (imageSrc0At(texCoord+Direction*2) + imageSrc0At(texCoord-Direction*2)) * 0.1216216
+	sum += (imageSrc0At(texCoord+Direction*3) + imageSrc0At(texCoord-Direction*3)) * 0.054054
+	sum += (imageSrc0At(texCoord+Direction*4) + imageSrc0At(texCoord-Direction*4)) * 0.016216
+
+	return sum * Amount
+}
+`
+)
+
+// builtinShaders caches compiled built in shaders by source
+var builtinShaders = make(map[string]*ebiten.Shader)
+
+func builtinShader(name, src string) (*ebiten.Shader, error) {
+	if shader, ok := builtinShaders[src]; ok {
+		return shader, nil
+	}
+
+	shader, err := ebiten.NewShader([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("compiling %v shader: %w", name, err)
+	}
+
+	builtinShaders[src] = shader
+
+	return shader, nil
+}
+
+// ShaderPass is a post process pass drawing the screen with a Kage shader,
+// the screen is the first source image of the shader.
+// Tween uniforms with NewUniformTween to animate the pass.
+type ShaderPass struct {
+	shader   *ebiten.Shader
+	uniforms ShaderUniforms
+}
+
+func NewShaderPass(shader *ebiten.Shader) *ShaderPass {
+	return &ShaderPass{
+		shader:   shader,
+		uniforms: make(ShaderUniforms),
+	}
+}
+
+func (p *ShaderPass) Shader() *ebiten.Shader {
+	return p.shader
+}
+
+// Uniforms returns our uniforms, changes are used on the next draw
+func (p *ShaderPass) Uniforms() ShaderUniforms {
+	return p.uniforms
+}
+
+func (p *ShaderPass) Apply(dest, src *ebiten.Image) {
+	size := src.Bounds().Size()
+	dest.DrawRectShader(size.X, size.Y, p.shader, &ebiten.DrawRectShaderOptions{
+		Uniforms: p.uniforms,
+		Images:   [4]*ebiten.Image{src},
+	})
+}
+
+// NewVignettePass darkens the edges of the screen.
+// Uniforms are Intensity from 0 to 1, Radius where darkening starts from
+// 0 in the center to 1 in the corners, and Softness of the fade.
+func NewVignettePass() (*ShaderPass, error) {
+	shader, err := builtinShader("vignette", vignetteShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewShaderPass(shader)
+	p.uniforms.SetFloat("Intensity", 0.5)
+	p.uniforms.SetFloat("Radius", 0.5)
+	p.uniforms.SetFloat("Softness", 0.5)
+
+	return p, nil
+}
+
+// NewScanlinePass draws CRT scanlines over a slightly curved screen.
+// Uniforms are Intensity of the lines from 0 to 1, LineHeight in pixels
+// and Curvature of the screen where 0 is flat.
+func NewScanlinePass() (*ShaderPass, error) {
+	shader, err := builtinShader("scanline", scanlineShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewShaderPass(shader)
+	p.uniforms.SetFloat("Intensity", 0.25)
+	p.uniforms.SetFloat("LineHeight", 3)
+	p.uniforms.SetFloat("Curvature", 0.03)
+
+	return p, nil
+}
+
+// NewChromaticAberrationPass splits the red and blue channels apart
+// towards the edges of the screen. Offset is the split in pixels at the edges.
+func NewChromaticAberrationPass() (*ShaderPass, error) {
+	shader, err := builtinShader("chromatic aberration", aberrationShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewShaderPass(shader)
+	p.uniforms.SetFloat("Offset", 2)
+
+	return p, nil
+}
+
+// ColorGradePass remaps colors using a lookup table image.
+// The table is a strip of size blue slices, each slice is size by size
+// with red increasing to the right and green increasing downwards,
+// such as a 256 by 16 image for a size of 16.
+// Intensity from 0 to 1 blends between the original and graded colors.
+type ColorGradePass struct {
+	*ShaderPass
+
+	lut          *ebiten.Image
+	table        *ebiten.Image
+	isTableDirty bool
+}
+
+func NewColorGradePass(lut *ebiten.Image, size int) (*ColorGradePass, error) {
+	shader, err := builtinShader("color grade", colorGradeShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ColorGradePass{
+		ShaderPass: NewShaderPass(shader),
+		lut:        lut,
+	}
+
+	p.uniforms.SetFloat("LutSize", float64(size))
+	p.uniforms.SetFloat("Intensity", 1)
+
+	return p, nil
+}
+
+// LUT returns our lookup table image
+func (p *ColorGradePass) LUT() *ebiten.Image {
+	return p.lut
+}
+
+// SetLUT swaps our lookup table, the table is copied on our next Apply.
+// Call SetLUT again after drawing into a table that is already set.
+func (p *ColorGradePass) SetLUT(lut *ebiten.Image, size int) {
+	p.lut = lut
+	p.uniforms.SetFloat("LutSize", float64(size))
+	p.isTableDirty = true
+}
+
+func (p *ColorGradePass) Apply(dest, src *ebiten.Image) {
+	size := src.Bounds().Size()
+
+	// source images all need to be the same size so copy our lookup table
+	// into the corner of a screen sized image
+	if p.isTableDirty || p.table == nil || p.table.Bounds().Size() != size {
+		p.table = igloo.FitImage(p.table, size)
+		p.table.Clear()
+		p.table.DrawImage(p.lut, nil)
+		p.isTableDirty = false
+	}
+
+	dest.DrawRectShader(size.X, size.Y, p.shader, &ebiten.DrawRectShaderOptions{
+		Uniforms: p.uniforms,
+		Images:   [4]*ebiten.Image{src, p.table},
+	})
+}
+
+// BloomPass makes bright areas of the screen glow.
+// Uniforms are Threshold of brightness from 0 to 1 that glows,
+// Intensity of the glow and Radius of the glow in pixels.
+type BloomPass struct {
+	threshold *ebiten.Shader
+	blur      *ebiten.Shader
+	uniforms  ShaderUniforms
+	bright    *ebiten.Image
+	blurred   *ebiten.Image
+
+	// uniforms of each step are kept so we do not allocate every frame
+	thresholdUniforms  ShaderUniforms
+	horizontalUniforms ShaderUniforms
+	verticalUniforms   ShaderUniforms
+	horizontal         []float32
+	vertical           []float32
+}
+
+func NewBloomPass() (*BloomPass, error) {
+	threshold, err := builtinShader("bloom threshold", bloomThresholdShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	blur, err := builtinShader("bloom blur", bloomBlurShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &BloomPass{
+		threshold:  threshold,
+		blur:       blur,
+		uniforms:   make(ShaderUniforms),
+		horizontal: make([]float32, 2),
+		vertical:   make([]float32, 2),
+	}
+
+	p.thresholdUniforms = ShaderUniforms{"Threshold": float32(0)}
+	p.horizontalUniforms = ShaderUniforms{"Direction": p.horizontal, "Amount": float32(1)}
+	p.verticalUniforms = ShaderUniforms{"Direction": p.vertical, "Amount": float32(0)}
+
+	p.uniforms.SetFloat("Threshold", 0.7)
+	p.uniforms.SetFloat("Intensity", 1)
+	p.uniforms.SetFloat("Radius", 8)
+
+	return p, nil
+}
+
+// Uniforms returns our uniforms, changes are used on the next draw
+func (p *BloomPass) Uniforms() ShaderUniforms {
+	return p.uniforms
+}
+
+func (p *BloomPass) Apply(dest, src *ebiten.Image) {
+	size := src.Bounds().Size()
+	step := p.uniforms.Float("Radius") / 4
+
+	p.bright = igloo.FitImage(p.bright, size)
+	p.blurred = igloo.FitImage(p.blurred, size)
+
+	p.thresholdUniforms.SetFloat("Threshold", p.uniforms.Float("Threshold"))
+	p.horizontal[0] = float32(step)
+	p.vertical[1] = float32(step)
+	p.verticalUniforms.SetFloat("Amount", p.uniforms.Float("Intensity"))
+
+	p.bright.Clear()
+	p.bright.DrawRectShader(size.X, size.Y, p.threshold, &ebiten.DrawRectShaderOptions{
+		Uniforms: p.thresholdUniforms,
+		Images:   [4]*ebiten.Image{src},
+	})
+
+	p.blurred.Clear()
+	p.blurred.DrawRectShader(size.X, size.Y, p.blur, &ebiten.DrawRectShaderOptions{
+		Uniforms: p.horizontalUniforms,
+		Images:   [4]*ebiten.Image{p.bright},
+	})
+
+	p.bright.Clear()
+	p.bright.DrawRectShader(size.X, size.Y, p.blur, &ebiten.DrawRectShaderOptions{
+		Uniforms: p.verticalUniforms,
+		Images:   [4]*ebiten.Image{p.blurred},
+	})
+
+	dest.DrawImage(src, nil)
+	dest.DrawImage(p.bright, &ebiten.DrawImageOptions{
+		CompositeMode: ebiten.CompositeModeLighter,
+	})
+}
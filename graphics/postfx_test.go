@@ -0,0 +1,125 @@
+package graphics_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/graphics"
+)
+
+func TestBuiltinPostPasses(t *testing.T) {
+	tests := map[string]func() (igloo.PostPass, error){
+		"vignette": func() (igloo.PostPass, error) {
+			return graphics.NewVignettePass()
+		},
+		"scanline": func() (igloo.PostPass, error) {
+			return graphics.NewScanlinePass()
+		},
+		"chromatic aberration": func() (igloo.PostPass, error) {
+			return graphics.NewChromaticAberrationPass()
+		},
+		"color grade": func() (igloo.PostPass, error) {
+			return graphics.NewColorGradePass(ebiten.NewImage(16, 4), 4)
+		},
+		"bloom": func() (igloo.PostPass, error) {
+			return graphics.NewBloomPass()
+		},
+	}
+
+	for name, newPass := range tests {
+		t.Run(name, func(t *testing.T) {
+			pass, err := newPass()
+			if err != nil {
+				t.Fatalf("expected: no error, got: %v", err)
+			}
+
+			chain := igloo.NewPostChain()
+			chain.Add(name, pass)
+			chain.Draw(ebiten.NewImage(32, 32), ebiten.NewImage(32, 32))
+		})
+	}
+}
+
+func TestPostPassPixels(t *testing.T) {
+	// every color grades to red
+	redLUT := ebiten.NewImage(4, 2)
+	redLUT.Fill(color.RGBA{R: 0xff, A: 0xff})
+
+	tests := map[string]struct {
+		newPass func() (igloo.PostPass, error)
+		point   image.Point
+		check   func(clr color.RGBA) bool
+	}{
+		"vignette keeps the center": {
+			newPass: func() (igloo.PostPass, error) { return graphics.NewVignettePass() },
+			point:   image.Pt(16, 16),
+			check:   func(clr color.RGBA) bool { return clr.R == 0xff },
+		},
+		"vignette darkens the corners": {
+			newPass: func() (igloo.PostPass, error) { return graphics.NewVignettePass() },
+			point:   image.Pt(0, 0),
+			check:   func(clr color.RGBA) bool { return clr.R < 0xa0 && clr.A == 0xff },
+		},
+		"color grade": {
+			newPass: func() (igloo.PostPass, error) {
+				return graphics.NewColorGradePass(redLUT, 2)
+			},
+			point: image.Pt(16, 16),
+			check: func(clr color.RGBA) bool { return clr.R > 0xf0 && clr.G < 0x10 && clr.B < 0x10 },
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pass, err := tc.newPass()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src := ebiten.NewImage(32, 32)
+			src.Fill(color.White)
+
+			dest := ebiten.NewImage(32, 32)
+			pass.Apply(dest, src)
+
+			if clr := pixelAt(t, dest, tc.point.X, tc.point.Y); !tc.check(clr) {
+				t.Fatalf("unexpected color at %v: %v", tc.point, clr)
+			}
+		})
+	}
+}
+
+func TestColorGradePassSetLUT(t *testing.T) {
+	redLUT := ebiten.NewImage(4, 2)
+	redLUT.Fill(color.RGBA{R: 0xff, A: 0xff})
+
+	greenLUT := ebiten.NewImage(4, 2)
+	greenLUT.Fill(color.RGBA{G: 0xff, A: 0xff})
+
+	pass, err := graphics.NewColorGradePass(redLUT, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := ebiten.NewImage(32, 32)
+	src.Fill(color.White)
+
+	dest := ebiten.NewImage(32, 32)
+	pass.Apply(dest, src)
+
+	pass.SetLUT(greenLUT, 2)
+	dest.Clear()
+	pass.Apply(dest, src)
+
+	if pass.LUT() != greenLUT {
+		t.Fatalf("expected: green lut, got: %v", pass.LUT())
+	}
+
+	if clr := pixelAt(t, dest, 16, 16); clr.G < 0xf0 || clr.R > 0x10 {
+		t.Fatalf("expected: green after swapping the lut, got: %v", clr)
+	}
+}
@@ -0,0 +1,417 @@
+package graphics
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// RichRun is a piece of rich text sharing a single style,
+// image runs have no text and only an Image name.
+type RichRun struct {
+	Text   string
+	Image  string
+	Bold   bool
+	Italic bool
+	// Size selects a font registered at that size, zero is the default size
+	Size float64
+	// Color tints the run, nil uses the visual color
+	Color color.Color
+	// Newline starts a new line before this run
+	Newline bool
+}
+
+// ParseRichText parses markup into runs. Supported tags are [b], [i],
+// [color=#f00], [size=24] and their closing tags such as [/b], along with
+// [img=name] for inline images. Use [[ for a literal bracket,
+// unknown tags are kept as text.
+func ParseRichText(markup string) []RichRun {
+	p := &richTextParser{}
+
+	for len(markup) > 0 {
+		switch {
+		case strings.HasPrefix(markup, "[["):
+			p.current.WriteByte('[')
+			markup = markup[2:]
+
+			continue
+		case markup[0] == '\n':
+			p.newline()
+			markup = markup[1:]
+
+			continue
+		case markup[0] != '[':
+			p.current.WriteByte(markup[0])
+			markup = markup[1:]
+
+			continue
+		}
+
+		end := strings.IndexByte(markup, ']')
+		if end < 0 {
+			p.current.WriteString(markup)
+			break
+		}
+
+		name, value, _ := strings.Cut(markup[1:end], "=")
+
+		if isRichTag(name, value) {
+			p.flush()
+			p.tag(name, value)
+		} else {
+			p.current.WriteString(markup[:end+1])
+		}
+
+		markup = markup[end+1:]
+	}
+
+	p.flush()
+
+	return p.runs
+}
+
+// richTextParser tracks the open tags while parsing markup
+type richTextParser struct {
+	runs      []RichRun
+	current   strings.Builder
+	bold      int
+	italic    int
+	colors    []color.Color
+	sizes     []float64
+	isNewline bool
+}
+
+// style returns an empty run in the current style
+func (p *richTextParser) style() RichRun {
+	run := RichRun{Bold: p.bold > 0, Italic: p.italic > 0, Newline: p.isNewline}
+	if len(p.colors) > 0 {
+		run.Color = p.colors[len(p.colors)-1]
+	}
+
+	if len(p.sizes) > 0 {
+		run.Size = p.sizes[len(p.sizes)-1]
+	}
+
+	return run
+}
+
+func (p *richTextParser) add(run RichRun) {
+	p.runs = append(p.runs, run)
+	p.isNewline = false
+}
+
+// flush adds any pending text as a run
+func (p *richTextParser) flush() {
+	if p.current.Len() == 0 {
+		return
+	}
+
+	run := p.style()
+	run.Text = p.current.String()
+	p.current.Reset()
+	p.add(run)
+}
+
+func (p *richTextParser) newline() {
+	p.flush()
+
+	// empty lines still need a run to take up space
+	if p.isNewline || len(p.runs) == 0 {
+		p.add(p.style())
+	}
+
+	p.isNewline = true
+}
+
+// tag applies a tag already checked with isRichTag
+func (p *richTextParser) tag(name, value string) {
+	switch name {
+	case "b":
+		p.bold++
+	case "/b":
+		p.bold = mathf.ClampInt(p.bold-1, 0, p.bold)
+	case "i":
+		p.italic++
+	case "/i":
+		p.italic = mathf.ClampInt(p.italic-1, 0, p.italic)
+	case "color":
+		c, _ := parseRichColor(value)
+		p.colors = append(p.colors, c)
+	case "/color":
+		if len(p.colors) > 0 {
+			p.colors = p.colors[:len(p.colors)-1]
+		}
+	case "size":
+		size, _ := strconv.ParseFloat(value, 64)
+		p.sizes = append(p.sizes, size)
+	case "/size":
+		if len(p.sizes) > 0 {
+			p.sizes = p.sizes[:len(p.sizes)-1]
+		}
+	case "img":
+		run := p.style()
+		run.Image = value
+		p.add(run)
+	}
+}
+
+// isRichTag returns whether or not a tag and its value are supported
+func isRichTag(name, value string) bool {
+	switch name {
+	case "b", "/b", "i", "/i", "/color", "/size":
+		return true
+	case "color":
+		_, err := parseRichColor(value)
+		return err == nil
+	case "size":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case "img":
+		return value != ""
+	default:
+		return false
+	}
+}
+
+// parseRichColor parses #rgb, #rgba, #rrggbb and #rrggbbaa colors
+func parseRichColor(value string) (color.Color, error) {
+	hex := strings.TrimPrefix(value, "#")
+
+	if len(hex) == 3 || len(hex) == 4 {
+		var expanded strings.Builder
+
+		for _, r := range hex {
+			expanded.WriteRune(r)
+			expanded.WriteRune(r)
+		}
+
+		hex = expanded.String()
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("parsing color %q: invalid length", value)
+	}
+
+	rgba, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing color %q: %w", value, err)
+	}
+
+	return color.NRGBA{
+		R: uint8(rgba >> 24),
+		G: uint8(rgba >> 16),
+		B: uint8(rgba >> 8),
+		A: uint8(rgba),
+	}, nil
+}
+
+// RichFontStyle selects which font a run is drawn with
+type RichFontStyle struct {
+	Bold   bool
+	Italic bool
+	// Size of the font, zero for the default size
+	Size float64
+}
+
+// richPlacement is a run with its font and position resolved
+type richPlacement struct {
+	run    RichRun
+	font   *content.Font
+	sprite *content.Sprite
+	x      float64
+	// baseline of the line the run is on
+	baseline float64
+}
+
+// RichTextVisual draws text with inline styles and images,
+// see ParseRichText for the supported markup.
+type RichTextVisual struct {
+	*igloo.Visualer
+	ebiten.ColorM
+
+	markup     string
+	runs       []RichRun
+	fonts      map[RichFontStyle]*content.Font
+	images     map[string]*content.Sprite
+	placements []richPlacement
+	width      float64
+	height     float64
+	isDirty    bool
+}
+
+func NewRichTextVisual() *RichTextVisual {
+	v := &RichTextVisual{
+		fonts:   make(map[RichFontStyle]*content.Font),
+		images:  make(map[string]*content.Sprite),
+		isDirty: true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+// SetFont registers a font for a style, the zero style is the default font.
+// Runs use the font matching their style, then the same style at the default
+// size and finally the default font.
+func (v *RichTextVisual) SetFont(style RichFontStyle, f *content.Font) {
+	v.fonts[style] = f
+	v.isDirty = true
+}
+
+// SetImage registers a sprite used by [img=name] tags
+func (v *RichTextVisual) SetImage(name string, sprite *content.Sprite) {
+	v.images[name] = sprite
+	v.isDirty = true
+}
+
+func (v *RichTextVisual) Text() string {
+	return v.markup
+}
+
+// SetText parses and sets our markup
+func (v *RichTextVisual) SetText(markup string) {
+	if v.markup == markup {
+		return
+	}
+
+	v.markup = markup
+	v.runs = ParseRichText(markup)
+	v.isDirty = true
+}
+
+func (v *RichTextVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *RichTextVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *RichTextVisual) NativeSize() (float64, float64) {
+	v.layout()
+	return v.width, v.height
+}
+
+func (v *RichTextVisual) font(run RichRun) *content.Font {
+	style := RichFontStyle{Bold: run.Bold, Italic: run.Italic, Size: run.Size}
+	if f, ok := v.fonts[style]; ok {
+		return f
+	}
+
+	style.Size = 0
+	if f, ok := v.fonts[style]; ok {
+		return f
+	}
+
+	return v.fonts[RichFontStyle{}]
+}
+
+// layout places each run on a line with every run on a line
+// sharing the same baseline.
+func (v *RichTextVisual) layout() {
+	v.placements = v.placements[:0]
+	v.width = 0
+	v.height = 0
+
+	lineStart := 0
+	x, ascent, descent := 0.0, 0.0, 0.0
+
+	endLine := func() {
+		for i := lineStart; i < len(v.placements); i++ {
+			v.placements[i].baseline = v.height + ascent
+		}
+
+		v.width = math.Max(v.width, x)
+		v.height += ascent + descent
+		lineStart = len(v.placements)
+		x, ascent, descent = 0, 0, 0
+	}
+
+	for i, run := range v.runs {
+		if run.Newline && i > 0 {
+			endLine()
+		}
+
+		f := v.font(run)
+		placement := richPlacement{run: run, font: f, x: x}
+
+		if run.Image != "" {
+			placement.sprite = v.images[run.Image]
+			if placement.sprite == nil {
+				continue
+			}
+
+			// images sit on the baseline
+			w, h := placement.sprite.NativeSize()
+			x += w
+			ascent = math.Max(ascent, h)
+		} else if f != nil {
+			x += float64(font.MeasureString(f.Face, run.Text)) / 64
+			ascent = math.Max(ascent, f.Ascent())
+			descent = math.Max(descent, f.LineHeight()-f.Ascent())
+		} else {
+			continue
+		}
+
+		v.placements = append(v.placements, placement)
+	}
+
+	endLine()
+}
+
+func (v *RichTextVisual) Draw(dest *ebiten.Image) {
+	geom := v.Transform.GeoM()
+
+	for _, p := range v.placements {
+		opts := &ebiten.DrawImageOptions{}
+
+		if p.run.Color != nil {
+			opts.ColorM.ScaleWithColor(p.run.Color)
+		}
+
+		opts.ColorM.Concat(v.ColorM)
+
+		if p.sprite != nil {
+			_, h := p.sprite.NativeSize()
+
+			opts.GeoM = p.sprite.RegionGeoM()
+			opts.GeoM.Translate(p.x, p.baseline-h)
+			opts.GeoM.Concat(geom)
+			opts.ColorM.Concat(p.sprite.ColorM)
+			opts.Filter = p.sprite.Filter
+			opts.CompositeMode = p.sprite.CompositeMode
+
+			dest.DrawImage(p.sprite.Image, opts)
+
+			continue
+		}
+
+		opts.GeoM.Translate(p.x, p.baseline)
+		opts.GeoM.Concat(geom)
+		opts.Filter = p.font.Filter
+		opts.CompositeMode = p.font.CompositeMode
+
+		text.DrawWithOptions(dest, p.run.Text, p.font, opts)
+	}
+}
@@ -0,0 +1,86 @@
+package graphics_test
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+)
+
+func TestParseRichText(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+
+	tests := map[string]struct {
+		markup   string
+		expected []graphics.RichRun
+	}{
+		"plain": {
+			markup:   "hello",
+			expected: []graphics.RichRun{{Text: "hello"}},
+		},
+		"nested styles": {
+			markup: "a[b]b[color=#f00]c[/color][/b][size=24]d",
+			expected: []graphics.RichRun{
+				{Text: "a"},
+				{Text: "b", Bold: true},
+				{Text: "c", Bold: true, Color: red},
+				{Text: "d", Size: 24},
+			},
+		},
+		"image": {
+			markup: "[i]x[img=coin][/i]",
+			expected: []graphics.RichRun{
+				{Text: "x", Italic: true},
+				{Image: "coin", Italic: true},
+			},
+		},
+		"newlines": {
+			markup: "a\n\nb",
+			expected: []graphics.RichRun{
+				{Text: "a"},
+				{Newline: true},
+				{Text: "b", Newline: true},
+			},
+		},
+		"escaped and unknown tags": {
+			markup:   "[[b] [wave] [color=nope]",
+			expected: []graphics.RichRun{{Text: "[b] [wave] [color=nope]"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			runs := graphics.ParseRichText(tc.markup)
+			if !reflect.DeepEqual(runs, tc.expected) {
+				t.Fatalf("expected: %+v, got: %+v", tc.expected, runs)
+			}
+		})
+	}
+}
+
+func TestRichTextVisualNativeSize(t *testing.T) {
+	regular := &content.Font{Face: basicfont.Face7x13}
+	coin := &content.Sprite{Image: ebiten.NewImage(4, 20)}
+
+	v := graphics.NewRichTextVisual()
+	v.SetFont(graphics.RichFontStyle{}, regular)
+	v.SetImage("coin", coin)
+	v.SetText("ab[img=coin]\n[b]cd[/b]")
+
+	// the first line is as tall as the coin sitting on the baseline
+	expectedWidth := 7*2 + 4.0
+	expectedHeight := 20 + regular.LineHeight() - regular.Ascent() + regular.LineHeight()
+
+	width, height := v.NativeSize()
+	if width != expectedWidth || height != expectedHeight {
+		t.Fatalf(
+			"expected: %v, %v, got: %v, %v",
+			expectedWidth, expectedHeight, width, height,
+		)
+	}
+}
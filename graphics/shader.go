@@ -0,0 +1,218 @@
+package graphics
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// ShaderUniforms are shader uniform values by name
+type ShaderUniforms map[string]any
+
+// SetFloat sets a float uniform
+func (u ShaderUniforms) SetFloat(name string, value float64) {
+	u[name] = float32(value)
+}
+
+// Float returns a float uniform or zero if unset
+func (u ShaderUniforms) Float(name string) float64 {
+	value, _ := u[name].(float32)
+	return float64(value)
+}
+
+// SetVec2 sets a vec2 uniform
+func (u ShaderUniforms) SetVec2(name string, value mathf.Vec2) {
+	u[name] = []float32{float32(value.X), float32(value.Y)}
+}
+
+// Vec2 returns a vec2 uniform or a zero vector if unset
+func (u ShaderUniforms) Vec2(name string) mathf.Vec2 {
+	value, _ := u[name].([]float32)
+	if len(value) != 2 {
+		return mathf.Vec2Zero
+	}
+
+	return mathf.Vec2{X: float64(value[0]), Y: float64(value[1])}
+}
+
+// SetColor sets a vec4 uniform to a premultiplied color from 0 to 1
+func (u ShaderUniforms) SetColor(name string, value color.Color) {
+	r, g, b, a := value.RGBA()
+	u[name] = []float32{
+		float32(r) / 0xffff,
+		float32(g) / 0xffff,
+		float32(b) / 0xffff,
+		float32(a) / 0xffff,
+	}
+}
+
+// ShaderVisual draws a rectangle using a Kage shader.
+// The rectangle is the size of the first source image, or the size set
+// with SetSize when there are no source images.
+type ShaderVisual struct {
+	*igloo.Visualer
+
+	shader   *ebiten.Shader
+	images   [4]*ebiten.Image
+	uniforms ShaderUniforms
+	width    float64
+	height   float64
+	isDirty  bool
+}
+
+func NewShaderVisual() *ShaderVisual {
+	v := &ShaderVisual{
+		uniforms: make(ShaderUniforms),
+		isDirty:  true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+func (v *ShaderVisual) Shader() *ebiten.Shader {
+	return v.shader
+}
+
+func (v *ShaderVisual) SetShader(shader *ebiten.Shader) {
+	v.shader = shader
+}
+
+// Uniforms returns our uniforms, changes are used on the next draw
+func (v *ShaderVisual) Uniforms() ShaderUniforms {
+	return v.uniforms
+}
+
+// Image returns the source image at index, from 0 to 3
+func (v *ShaderVisual) Image(index int) *ebiten.Image {
+	return v.images[index]
+}
+
+// SetImage binds a source image from 0 to 3, all source images
+// need to be the same size. Images of a different size are not bound.
+func (v *ShaderVisual) SetImage(index int, img *ebiten.Image) error {
+	if v.images[index] == img {
+		return nil
+	}
+
+	if img != nil {
+		size := img.Bounds().Size()
+
+		for i, other := range v.images {
+			if i == index || other == nil {
+				continue
+			}
+
+			if otherSize := other.Bounds().Size(); otherSize != size {
+				return fmt.Errorf(
+					"shader image %v is %vx%v, image %v is %vx%v",
+					index, size.X, size.Y, i, otherSize.X, otherSize.Y,
+				)
+			}
+		}
+	}
+
+	v.images[index] = img
+	v.isDirty = true
+
+	return nil
+}
+
+// SetSize sets our native size when we have no source images
+func (v *ShaderVisual) SetSize(width, height float64) {
+	if v.width == width && v.height == height {
+		return
+	}
+
+	v.width = width
+	v.height = height
+	v.isDirty = true
+}
+
+func (v *ShaderVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *ShaderVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *ShaderVisual) NativeSize() (float64, float64) {
+	if v.images[0] != nil {
+		size := v.images[0].Bounds().Size()
+		return float64(size.X), float64(size.Y)
+	}
+
+	return v.width, v.height
+}
+
+func (v *ShaderVisual) Draw(dest *ebiten.Image) {
+	if v.shader == nil {
+		return
+	}
+
+	width, height := v.NativeSize()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	dest.DrawRectShader(int(width), int(height), v.shader, &ebiten.DrawRectShaderOptions{
+		GeoM:     v.Transform.GeoM(),
+		Uniforms: v.uniforms,
+		Images:   v.images,
+	})
+}
+
+// Shader tweens
+
+func NewUniformTween(
+	target ShaderUniforms,
+	name string,
+	start, end, duration float64,
+	options ...mathf.TweenOption,
+) *mathf.Tween {
+	t := mathf.NewTween(
+		duration,
+		mathf.TweenUpdateFunc(func(value float64) {
+			target.SetFloat(name, mathf.Lerp(start, end, value))
+		}),
+	)
+
+	for _, opt := range options {
+		opt(t)
+	}
+
+	return t
+}
+
+func NewUniformVec2Tween(
+	target ShaderUniforms,
+	name string,
+	start, end mathf.Vec2,
+	duration float64,
+	options ...mathf.TweenOption,
+) *mathf.Tween {
+	t := mathf.NewTween(
+		duration,
+		mathf.TweenUpdateFunc(func(value float64) {
+			target.SetVec2(name, mathf.Vec2Lerp(start, end, value))
+		}),
+	)
+
+	for _, opt := range options {
+		opt(t)
+	}
+
+	return t
+}
@@ -0,0 +1,168 @@
+package graphics_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+const tintShaderSrc = `//kage:unit pixels
+
+package main
+
+var Tint vec4
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	return imageSrc0At(src)*Tint + imageSrc1At(src)
+}
+`
+
+func TestShaderVisualDraw(t *testing.T) {
+	shader, err := ebiten.NewShader([]byte(tintShaderSrc))
+	if err != nil {
+		t.Fatalf("expected: no error, got: %v", err)
+	}
+
+	white := ebiten.NewImage(8, 8)
+	white.Fill(color.White)
+
+	blue := ebiten.NewImage(8, 8)
+	blue.Fill(color.RGBA{B: 0xff, A: 0xff})
+
+	tests := map[string]struct {
+		images   []*ebiten.Image
+		tint     color.Color
+		expected color.RGBA
+	}{
+		"tints the first image": {
+			images:   []*ebiten.Image{white},
+			tint:     color.RGBA{R: 0xff, A: 0xff},
+			expected: color.RGBA{R: 0xff, A: 0xff},
+		},
+		"adds the second image": {
+			images:   []*ebiten.Image{white, blue},
+			tint:     color.RGBA{G: 0xff, A: 0xff},
+			expected: color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := mathf.NewTransform()
+			root.SetSize(16, 16)
+			root.SetNaturalWidth(16)
+			root.SetNaturalHeight(16)
+			root.Build(nil)
+
+			v := graphics.NewShaderVisual()
+			v.SetShader(shader)
+			v.SetVisible(true)
+			v.Uniforms().SetColor("Tint", tc.tint)
+
+			for i, img := range tc.images {
+				if err := v.SetImage(i, img); err != nil {
+					t.Fatalf("expected: no error, got: %v", err)
+				}
+			}
+
+			v.Layout(root, root)
+
+			dest := ebiten.NewImage(16, 16)
+			v.Visualer.Draw(dest)
+
+			if got := pixelAt(t, dest, 4, 4); got != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, got)
+			}
+
+			if got := pixelAt(t, dest, 12, 12); got.A != 0 {
+				t.Fatalf("expected: nothing outside our images, got: %v", got)
+			}
+		})
+	}
+}
+
+func TestShaderVisualSetImageSize(t *testing.T) {
+	v := graphics.NewShaderVisual()
+	small := ebiten.NewImage(8, 8)
+	large := ebiten.NewImage(16, 8)
+
+	if err := v.SetImage(0, small); err != nil {
+		t.Fatalf("expected: no error, got: %v", err)
+	}
+
+	if err := v.SetImage(1, large); err == nil {
+		t.Fatal("expected: an error for a different size, got: nil")
+	}
+
+	if v.Image(1) != nil {
+		t.Fatalf("expected: mismatched image not bound, got: %v", v.Image(1))
+	}
+
+	// replacing the only image can change the size
+	if err := v.SetImage(0, large); err != nil {
+		t.Fatalf("expected: no error, got: %v", err)
+	}
+}
+
+func TestUniformTweens(t *testing.T) {
+	tests := map[string]struct {
+		newTween func(uniforms graphics.ShaderUniforms) *mathf.Tween
+		value    func(uniforms graphics.ShaderUniforms) mathf.Vec2
+	}{
+		"float": {
+			newTween: func(uniforms graphics.ShaderUniforms) *mathf.Tween {
+				return graphics.NewUniformTween(uniforms, "Amount", 0, 2, 1, mathf.TweenPlay())
+			},
+			value: func(uniforms graphics.ShaderUniforms) mathf.Vec2 {
+				return mathf.Vec2{X: uniforms.Float("Amount"), Y: uniforms.Float("Amount")}
+			},
+		},
+		"vec2": {
+			newTween: func(uniforms graphics.ShaderUniforms) *mathf.Tween {
+				return graphics.NewUniformVec2Tween(
+					uniforms, "Amount",
+					mathf.Vec2Zero, mathf.Vec2{X: 2, Y: 2},
+					1, mathf.TweenPlay(),
+				)
+			},
+			value: func(uniforms graphics.ShaderUniforms) mathf.Vec2 {
+				return uniforms.Vec2("Amount")
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			uniforms := make(graphics.ShaderUniforms)
+			tween := tc.newTween(uniforms)
+			ticksPerSecond := ebiten.TPS()
+
+			// each tick sets the value before moving forward
+			checks := []struct {
+				ticks    int
+				expected float64
+			}{
+				{ticks: 1, expected: 0},
+				{ticks: ticksPerSecond / 2, expected: 1},
+				{ticks: ticksPerSecond / 2, expected: 2},
+			}
+
+			for _, check := range checks {
+				for i := 0; i < check.ticks; i++ {
+					tween.Tick()
+				}
+
+				value := tc.value(uniforms)
+				if math.Abs(value.X-check.expected) > 1e-3 ||
+					math.Abs(value.Y-check.expected) > 1e-3 {
+					t.Fatalf("expected: %v, got: %v", check.expected, value)
+				}
+			}
+		})
+	}
+}
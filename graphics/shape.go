@@ -0,0 +1,457 @@
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// minEllipseSegments keeps small circles round
+const minEllipseSegments = 16
+
+// ellipseSegmentLength is roughly how many pixels each ellipse segment covers
+const ellipseSegmentLength = 4.0
+
+// shapeImage is a white pixel our triangles sample from
+var shapeImage *ebiten.Image
+
+func whiteShapeImage() *ebiten.Image {
+	if shapeImage == nil {
+		img := ebiten.NewImage(3, 3)
+		img.Fill(color.White)
+
+		// avoid sampling the edge of the image when anti aliasing
+		shapeImage = img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	}
+
+	return shapeImage
+}
+
+// Shape adds a path to fill a width and height, shapes are rebuilt
+// at the size of the Transform bounds so strokes are never stretched.
+// Shapes that are not closed are never filled, only stroked.
+type Shape interface {
+	AppendPath(path *vector.Path, width, height float64)
+	Closed() bool
+}
+
+// RectShape is a rectangle filling the Transform
+type RectShape struct{}
+
+func (s RectShape) Closed() bool {
+	return true
+}
+
+func (s RectShape) AppendPath(path *vector.Path, width, height float64) {
+	w, h := float32(width), float32(height)
+
+	path.MoveTo(0, 0)
+	path.LineTo(w, 0)
+	path.LineTo(w, h)
+	path.LineTo(0, h)
+	path.Close()
+}
+
+// RoundedRectShape is a rectangle with rounded corners, the radius
+// is reduced when the rectangle is too small to fit it.
+type RoundedRectShape struct {
+	Radius float64
+}
+
+func (s RoundedRectShape) Closed() bool {
+	return true
+}
+
+func (s RoundedRectShape) AppendPath(path *vector.Path, width, height float64) {
+	radius := math.Min(s.Radius, math.Min(width, height)/2)
+	if radius <= 0 {
+		RectShape{}.AppendPath(path, width, height)
+		return
+	}
+
+	w, h, r := float32(width), float32(height), float32(radius)
+
+	path.MoveTo(r, 0)
+	path.ArcTo(w, 0, w, h, r)
+	path.ArcTo(w, h, 0, h, r)
+	path.ArcTo(0, h, 0, 0, r)
+	path.ArcTo(0, 0, w, 0, r)
+	path.Close()
+}
+
+// EllipseShape is a circle or ellipse filling the Transform
+type EllipseShape struct{}
+
+func (s EllipseShape) Closed() bool {
+	return true
+}
+
+func (s EllipseShape) AppendPath(path *vector.Path, width, height float64) {
+	ArcShape{Start: 0, End: 2 * math.Pi}.AppendPath(path, width, height)
+}
+
+// ArcShape is part of an ellipse filling the Transform, angles are in
+// radians clockwise starting from the right.
+type ArcShape struct {
+	Start float64
+	End   float64
+	// Pie connects both ends to the center, otherwise the arc is left open
+	// and only stroked
+	Pie bool
+}
+
+// Closed returns true for pies and full circles
+func (s ArcShape) Closed() bool {
+	return s.Pie || math.Abs(s.End-s.Start) >= 2*math.Pi
+}
+
+func (s ArcShape) AppendPath(path *vector.Path, width, height float64) {
+	rx, ry := width/2, height/2
+	sweep := s.End - s.Start
+	full := math.Abs(sweep) >= 2*math.Pi
+	pie := s.Pie && !full
+
+	// ellipses are not supported by the vector package so we use segments
+	arcLength := math.Pi * (rx + ry) * math.Abs(sweep) / (2 * math.Pi)
+
+	segments := int(math.Ceil(arcLength / ellipseSegmentLength))
+	if segments < minEllipseSegments {
+		segments = minEllipseSegments
+	}
+
+	if pie {
+		path.MoveTo(float32(rx), float32(ry))
+	}
+
+	for i := 0; i <= segments; i++ {
+		angle := s.Start + sweep*float64(i)/float64(segments)
+		x := float32(rx + math.Cos(angle)*rx)
+		y := float32(ry + math.Sin(angle)*ry)
+
+		if i == 0 && !pie {
+			path.MoveTo(x, y)
+		} else {
+			path.LineTo(x, y)
+		}
+	}
+
+	if s.Closed() {
+		path.Close()
+	}
+}
+
+// PolylineShape is an open line through points, points are stretched
+// from their native size, the largest x and y, to the Transform size.
+// Lines are never filled, set a stroke to draw them.
+type PolylineShape struct {
+	Points []mathf.Vec2
+}
+
+func (s PolylineShape) NativeSize() (float64, float64) {
+	return pointsSize(s.Points)
+}
+
+func (s PolylineShape) Closed() bool {
+	return false
+}
+
+func (s PolylineShape) AppendPath(path *vector.Path, width, height float64) {
+	appendPoints(path, s.Points, width, height)
+}
+
+// PolygonShape is a closed shape through points, points are stretched
+// from their native size, the largest x and y, to the Transform size.
+// Overlapping areas are filled using the even odd rule.
+type PolygonShape struct {
+	Points []mathf.Vec2
+}
+
+func (s PolygonShape) NativeSize() (float64, float64) {
+	return pointsSize(s.Points)
+}
+
+func (s PolygonShape) Closed() bool {
+	return true
+}
+
+func (s PolygonShape) AppendPath(path *vector.Path, width, height float64) {
+	if appendPoints(path, s.Points, width, height) {
+		path.Close()
+	}
+}
+
+func pointsSize(points []mathf.Vec2) (float64, float64) {
+	var width, height float64
+
+	for _, p := range points {
+		width = math.Max(width, p.X)
+		height = math.Max(height, p.Y)
+	}
+
+	return width, height
+}
+
+// appendPoints adds a line through points scaled to fit a width
+// and height, returning false when there is nothing to draw.
+func appendPoints(path *vector.Path, points []mathf.Vec2, width, height float64) bool {
+	if len(points) < 2 {
+		return false
+	}
+
+	scaleX, scaleY := 1.0, 1.0
+	nativeWidth, nativeHeight := pointsSize(points)
+
+	if nativeWidth > 0 {
+		scaleX = width / nativeWidth
+	}
+
+	if nativeHeight > 0 {
+		scaleY = height / nativeHeight
+	}
+
+	path.MoveTo(float32(points[0].X*scaleX), float32(points[0].Y*scaleY))
+
+	for _, p := range points[1:] {
+		path.LineTo(float32(p.X*scaleX), float32(p.Y*scaleY))
+	}
+
+	return true
+}
+
+// ShapeVisual fills and strokes a Shape sized by our Transform,
+// strokes are centered on the edge of the shape.
+// Shapes with their own NativeSize, like polygons, use that size,
+// otherwise the size is set with SetSize.
+// Triangles are only rebuilt when our shape, stroke or bounds change,
+// so shapes changed in place need to be set again with SetShape.
+type ShapeVisual struct {
+	*igloo.Visualer
+
+	shape          Shape
+	fill           color.Color
+	stroke         color.Color
+	strokeOptions  vector.StrokeOptions
+	antiAlias      bool
+	width          float64
+	height         float64
+	fillVertices   []ebiten.Vertex
+	fillIndices    []uint16
+	strokeVertices []ebiten.Vertex
+	strokeIndices  []uint16
+	vertices       []ebiten.Vertex
+	builtWidth     float64
+	builtHeight    float64
+	needsBuild     bool
+	isDirty        bool
+}
+
+func NewShapeVisual(shape Shape) *ShapeVisual {
+	v := &ShapeVisual{
+		shape:      shape,
+		fill:       color.White,
+		antiAlias:  true,
+		needsBuild: true,
+		isDirty:    true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+func (v *ShapeVisual) Shape() Shape {
+	return v.shape
+}
+
+func (v *ShapeVisual) SetShape(shape Shape) {
+	v.shape = shape
+	v.needsBuild = true
+	v.isDirty = true
+}
+
+func (v *ShapeVisual) Fill() color.Color {
+	return v.fill
+}
+
+// SetFill sets our fill color, nil to only draw the stroke
+func (v *ShapeVisual) SetFill(fill color.Color) {
+	// only colors change unless we start or stop filling
+	if (fill == nil) != (v.fill == nil) {
+		v.needsBuild = true
+	}
+
+	v.fill = fill
+}
+
+func (v *ShapeVisual) Stroke() color.Color {
+	return v.stroke
+}
+
+// SetStroke sets our stroke color and width in pixels, nil to not draw the stroke
+func (v *ShapeVisual) SetStroke(stroke color.Color, width float64) {
+	if (stroke == nil) != (v.stroke == nil) || v.strokeOptions.Width != float32(width) {
+		v.needsBuild = true
+	}
+
+	v.stroke = stroke
+	v.strokeOptions.Width = float32(width)
+}
+
+func (v *ShapeVisual) StrokeOptions() vector.StrokeOptions {
+	return v.strokeOptions
+}
+
+// SetStrokeOptions sets the width, joins and caps of our stroke
+func (v *ShapeVisual) SetStrokeOptions(options vector.StrokeOptions) {
+	if v.strokeOptions == options {
+		return
+	}
+
+	v.strokeOptions = options
+	v.needsBuild = true
+}
+
+func (v *ShapeVisual) AntiAlias() bool {
+	return v.antiAlias
+}
+
+// SetAntiAlias smooths our edges, on by default
+func (v *ShapeVisual) SetAntiAlias(antiAlias bool) {
+	v.antiAlias = antiAlias
+}
+
+// SetSize sets our native size for shapes without one
+func (v *ShapeVisual) SetSize(width, height float64) {
+	if v.width == width && v.height == height {
+		return
+	}
+
+	v.width = width
+	v.height = height
+	v.isDirty = true
+}
+
+func (v *ShapeVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *ShapeVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *ShapeVisual) NativeSize() (float64, float64) {
+	if sizer, ok := v.shape.(igloo.NativeSizer); ok {
+		return sizer.NativeSize()
+	}
+
+	return v.width, v.height
+}
+
+func (v *ShapeVisual) Draw(dest *ebiten.Image) {
+	if v.shape == nil {
+		return
+	}
+
+	bounds := v.Transform.Bounds()
+	nativeWidth, nativeHeight := v.Transform.NaturalSize()
+
+	if bounds.Width <= 0 || bounds.Height <= 0 || nativeWidth <= 0 || nativeHeight <= 0 {
+		return
+	}
+
+	// draw in our final size by undoing the scale of our transform
+	var base ebiten.GeoM
+
+	base.Scale(nativeWidth/bounds.Width, nativeHeight/bounds.Height)
+	base.Concat(v.Transform.GeoM())
+
+	if v.needsBuild || v.builtWidth != bounds.Width || v.builtHeight != bounds.Height {
+		v.build(bounds.Width, bounds.Height)
+	}
+
+	if v.fill != nil {
+		v.drawTriangles(dest, v.fillVertices, v.fillIndices, v.fill, ebiten.EvenOdd, base)
+	}
+
+	if v.stroke != nil {
+		v.drawTriangles(dest, v.strokeVertices, v.strokeIndices, v.stroke, ebiten.FillAll, base)
+	}
+}
+
+// build our fill and stroke triangles at a width and height,
+// triangles are kept in our local space until they are drawn.
+func (v *ShapeVisual) build(width, height float64) {
+	var path vector.Path
+
+	v.shape.AppendPath(&path, width, height)
+	v.fillVertices = v.fillVertices[:0]
+	v.fillIndices = v.fillIndices[:0]
+	v.strokeVertices = v.strokeVertices[:0]
+	v.strokeIndices = v.strokeIndices[:0]
+
+	// open shapes would be closed by filling them
+	if v.fill != nil && v.shape.Closed() {
+		v.fillVertices, v.fillIndices = path.AppendVerticesAndIndicesForFilling(
+			v.fillVertices,
+			v.fillIndices,
+		)
+	}
+
+	if v.stroke != nil && v.strokeOptions.Width > 0 {
+		v.strokeVertices, v.strokeIndices = path.AppendVerticesAndIndicesForStroke(
+			v.strokeVertices,
+			v.strokeIndices,
+			&v.strokeOptions,
+		)
+	}
+
+	v.builtWidth = width
+	v.builtHeight = height
+	v.needsBuild = false
+}
+
+func (v *ShapeVisual) drawTriangles(
+	dest *ebiten.Image,
+	vertices []ebiten.Vertex,
+	indices []uint16,
+	clr color.Color,
+	fillRule ebiten.FillRule,
+	geom ebiten.GeoM,
+) {
+	if len(indices) == 0 {
+		return
+	}
+
+	r, g, b, a := clr.RGBA()
+	v.vertices = append(v.vertices[:0], vertices...)
+
+	for i := range v.vertices {
+		vert := &v.vertices[i]
+		x, y := geom.Apply(float64(vert.DstX), float64(vert.DstY))
+
+		vert.DstX, vert.DstY = float32(x), float32(y)
+		vert.SrcX, vert.SrcY = 1, 1
+		vert.ColorR = float32(r) / 0xffff
+		vert.ColorG = float32(g) / 0xffff
+		vert.ColorB = float32(b) / 0xffff
+		vert.ColorA = float32(a) / 0xffff
+	}
+
+	dest.DrawTriangles(v.vertices, indices, whiteShapeImage(), &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		FillRule:       fillRule,
+		AntiAlias:      v.antiAlias,
+	})
+}
@@ -0,0 +1,147 @@
+package graphics_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestShapeVisualNativeSize(t *testing.T) {
+	tests := map[string]struct {
+		shape     graphics.Shape
+		width     float64
+		height    float64
+		expectedW float64
+		expectedH float64
+	}{
+		"rect uses set size": {
+			shape:     graphics.RectShape{},
+			width:     10,
+			height:    4,
+			expectedW: 10,
+			expectedH: 4,
+		},
+		"polygon uses points": {
+			shape: graphics.PolygonShape{Points: []mathf.Vec2{
+				{X: 0, Y: 0}, {X: 8, Y: 2}, {X: 3, Y: 6},
+			}},
+			width:     10,
+			height:    4,
+			expectedW: 8,
+			expectedH: 6,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := graphics.NewShapeVisual(tc.shape)
+			v.SetSize(tc.width, tc.height)
+
+			w, h := v.NativeSize()
+			if w != tc.expectedW || h != tc.expectedH {
+				t.Fatalf("expected: %v, %v, got: %v, %v", tc.expectedW, tc.expectedH, w, h)
+			}
+		})
+	}
+}
+
+// newShapeTest lays out a 16x16 shape with a white fill and black stroke
+func newShapeTest(shape graphics.Shape) (*mathf.Transform, *graphics.ShapeVisual) {
+	root := mathf.NewTransform()
+	root.SetSize(32, 32)
+	root.SetNaturalWidth(32)
+	root.SetNaturalHeight(32)
+	root.Build(nil)
+
+	v := graphics.NewShapeVisual(shape)
+	v.SetSize(16, 16)
+	v.SetStroke(color.Black, 2)
+	v.SetVisible(true)
+	v.Layout(root, root)
+
+	return root, v
+}
+
+func TestShapeVisualDraw(t *testing.T) {
+	tests := map[string]struct {
+		shape       graphics.Shape
+		opaque      image.Point
+		transparent image.Point
+	}{
+		"rect": {
+			shape:       graphics.RectShape{},
+			opaque:      image.Pt(8, 8),
+			transparent: image.Pt(20, 20),
+		},
+		"rounded rect": {
+			shape:       graphics.RoundedRectShape{Radius: 6},
+			opaque:      image.Pt(8, 8),
+			transparent: image.Pt(0, 0),
+		},
+		"ellipse": {
+			shape:       graphics.EllipseShape{},
+			opaque:      image.Pt(8, 8),
+			transparent: image.Pt(1, 1),
+		},
+		"pie": {
+			shape:       graphics.ArcShape{Start: 0, End: 3, Pie: true},
+			opaque:      image.Pt(8, 12),
+			transparent: image.Pt(8, 3),
+		},
+		"polyline is never filled": {
+			shape:       graphics.PolylineShape{Points: []mathf.Vec2{{X: 0, Y: 0}, {X: 4, Y: 4}}},
+			opaque:      image.Pt(8, 8),
+			transparent: image.Pt(12, 3),
+		},
+		"polyline pointer is never filled": {
+			shape:       &graphics.PolylineShape{Points: []mathf.Vec2{{X: 0, Y: 0}, {X: 4, Y: 4}}},
+			opaque:      image.Pt(8, 8),
+			transparent: image.Pt(12, 3),
+		},
+		"open arc is never filled": {
+			shape:       graphics.ArcShape{Start: 0, End: 3},
+			opaque:      image.Pt(8, 15),
+			transparent: image.Pt(8, 12),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, v := newShapeTest(tc.shape)
+			dest := ebiten.NewImage(32, 32)
+			v.Draw(dest)
+
+			if clr := pixelAt(t, dest, tc.opaque.X, tc.opaque.Y); clr.A != 0xff {
+				t.Fatalf("expected: opaque at %v, got: %v", tc.opaque, clr)
+			}
+
+			if clr := pixelAt(t, dest, tc.transparent.X, tc.transparent.Y); clr.A != 0 {
+				t.Fatalf("expected: transparent at %v, got: %v", tc.transparent, clr)
+			}
+		})
+	}
+}
+
+func TestShapeVisualRebuildsOnResize(t *testing.T) {
+	root, v := newShapeTest(graphics.RectShape{})
+	v.Draw(ebiten.NewImage(32, 32))
+
+	v.SetSize(8, 8)
+	v.Layout(root, root)
+
+	dest := ebiten.NewImage(32, 32)
+	v.Draw(dest)
+
+	if clr := pixelAt(t, dest, 12, 12); clr.A != 0 {
+		t.Fatalf("expected: transparent outside of our new size, got: %v", clr)
+	}
+
+	if clr := pixelAt(t, dest, 4, 4); clr.A != 0xff {
+		t.Fatalf("expected: opaque inside of our new size, got: %v", clr)
+	}
+}
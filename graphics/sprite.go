@@ -1,6 +1,8 @@
 package graphics
 
 import (
+	"image"
+
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"github.com/miniscruff/igloo"
@@ -53,69 +55,65 @@ func (v *SpriteVisual) Clean() {
 }
 
 func (v *SpriteVisual) NativeSize() (float64, float64) {
-	pt := v.sprite.Image.Bounds().Size()
-	return float64(pt.X), float64(pt.Y)
+	return v.sprite.NativeSize()
+}
+
+// DrawBatch adds our sprite to a batch instead of drawing on our own
+func (v *SpriteVisual) DrawBatch(batch *SpriteBatch) {
+	batch.Add(v.sprite, v.Transform.GeoM())
 }
 
 func (v *SpriteVisual) Draw(dest *ebiten.Image) {
+	geom := v.sprite.RegionGeoM()
+	geom.Concat(v.Transform.GeoM())
+
 	dest.DrawImage(v.sprite.Image, &ebiten.DrawImageOptions{
-		GeoM:          v.Transform.GeoM(),
+		GeoM:          geom,
 		ColorM:        v.sprite.ColorM,
 		Filter:        v.sprite.Filter,
 		CompositeMode: v.sprite.CompositeMode,
 	})
 }
 
-/*
-// SpriteSheet represents a collection of images
-type SpriteSheet []*ebiten.Image
-
-func (ss *SpriteSheet) FrameAt(percent float64) *ebiten.Image {
-	count := float64(len(*ss))
-	return (*ss)[int(mathf.Lerp(0, count-1, percent))]
-}
-
-func SheetFromGrid(sheet *ebiten.Image, columns, rows, frames int) *SpriteSheet {
-	var images SpriteSheet = make([]*ebiten.Image, frames)
-
-	w, h := sheet.Size()
-	fw := w / columns
-	fh := h / rows
-
-	for y := 0; y < rows; y++ {
-		for x := 0; x < columns; x++ {
-			i := y*rows + x
-			images[i] = sheet.SubImage(
-				image.Rect(x*fw, y*fw, (x+1)*fw, (y+1)*fh),
-			).(*ebiten.Image)
-
-			if i == frames-1 {
-				goto done
-			}
+// SheetFromGrid cuts a sheet into frames equally sized sprites in a grid of
+// columns and rows, going left to right then top to bottom.
+func SheetFromGrid(sheet *ebiten.Image, columns, rows, frames int) []*content.Sprite {
+	sprites := make([]*content.Sprite, 0, frames)
+	bounds := sheet.Bounds()
+	fw := bounds.Dx() / columns
+	fh := bounds.Dy() / rows
+
+	for y := 0; y < rows && len(sprites) < frames; y++ {
+		for x := 0; x < columns && len(sprites) < frames; x++ {
+			corner := bounds.Min.Add(image.Pt(x*fw, y*fh))
+			rect := image.Rectangle{Min: corner, Max: corner.Add(image.Pt(fw, fh))}
+
+			sprites = append(sprites, &content.Sprite{
+				Image: sheet.SubImage(rect).(*ebiten.Image),
+				Page:  sheet,
+			})
 		}
 	}
 
-done:
-	return &images
+	return sprites
 }
 
-func NewFrameClip(
-	target *Sprite,
-	sheet *SpriteSheet,
-	duration float64,
-	options ...mathf.TweenOption,
-) *mathf.Tween {
-	t := mathf.NewTween(
-		duration,
-		mathf.TweenUpdateFunc(func(value float64) {
-			target.Image = sheet.FrameAt(value)
-		}),
-	)
-
-	for _, opt := range options {
-		opt(t)
+// NewAnimationFromSprites creates an animation playing sprites forward,
+// each frame lasting frameDuration seconds.
+func NewAnimationFromSprites(
+	name string,
+	sprites []*content.Sprite,
+	frameDuration float64,
+) *content.Animation {
+	anim := &content.Animation{
+		Name:      name,
+		Direction: content.AnimationForward,
+		Frames:    make([]content.Frame, len(sprites)),
+	}
+
+	for i, sprite := range sprites {
+		anim.Frames[i] = content.Frame{Sprite: sprite, Duration: frameDuration}
 	}
 
-	return t
+	return anim
 }
-*/
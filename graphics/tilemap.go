@@ -0,0 +1,224 @@
+package graphics
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// tilemapLayer is a layer along with how many cells its tiles can
+// overflow up and to the right, tiles larger than a cell are drawn
+// from the bottom left of the cell like in Tiled.
+type tilemapLayer struct {
+	*content.TileLayer
+	overflowCols int
+	overflowRows int
+}
+
+// TilemapVisual draws tile layers in order, only tiles inside of
+// the cull transform, usually the root transform, are drawn.
+type TilemapVisual struct {
+	*igloo.Visualer
+
+	layers  []tilemapLayer
+	cull    *mathf.Transform
+	isDirty bool
+}
+
+func NewTilemapVisual() *TilemapVisual {
+	v := &TilemapVisual{
+		isDirty: true,
+	}
+
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	return v
+}
+
+// SetTilemap draws every tile layer of a tile map
+func (v *TilemapVisual) SetTilemap(tilemap *content.Tilemap) {
+	v.SetLayers(tilemap.TileLayers...)
+}
+
+// SetLayers draws only the given layers, in order
+func (v *TilemapVisual) SetLayers(layers ...*content.TileLayer) {
+	v.layers = make([]tilemapLayer, len(layers))
+
+	for i, layer := range layers {
+		v.layers[i] = newTilemapLayer(layer)
+	}
+
+	v.isDirty = true
+}
+
+// Layers returns the layers we are drawing
+func (v *TilemapVisual) Layers() []*content.TileLayer {
+	layers := make([]*content.TileLayer, len(v.layers))
+	for i, layer := range v.layers {
+		layers[i] = layer.TileLayer
+	}
+
+	return layers
+}
+
+// SetCullTransform sets the transform tiles have to overlap to be drawn,
+// this is normally the root transform passed to Layout.
+// Without a cull transform tiles outside of the destination image are skipped.
+func (v *TilemapVisual) SetCullTransform(cull *mathf.Transform) {
+	v.cull = cull
+}
+
+func (v *TilemapVisual) IsDirty() bool {
+	return v.isDirty
+}
+
+func (v *TilemapVisual) Clean() {
+	v.isDirty = false
+}
+
+func (v *TilemapVisual) NativeSize() (float64, float64) {
+	var width, height float64
+
+	for _, layer := range v.layers {
+		width = math.Max(width, layer.OffsetX+float64(layer.Width*layer.TileWidth))
+		height = math.Max(height, layer.OffsetY+float64(layer.Height*layer.TileHeight))
+	}
+
+	return width, height
+}
+
+func (v *TilemapVisual) Draw(dest *ebiten.Image) {
+	geom := v.Transform.GeoM()
+	view, ok := v.localView(dest)
+
+	if !ok {
+		return
+	}
+
+	opts := &ebiten.DrawImageOptions{}
+
+	for _, layer := range v.layers {
+		if !layer.Visible || layer.Opacity <= 0 || layer.TileWidth <= 0 || layer.TileHeight <= 0 {
+			continue
+		}
+
+		tw := float64(layer.TileWidth)
+		th := float64(layer.TileHeight)
+
+		minX := int(math.Floor((view.X-layer.OffsetX)/tw)) - layer.overflowCols
+		minY := int(math.Floor((view.Y - layer.OffsetY) / th))
+		maxX := int(math.Ceil((view.Right() - layer.OffsetX) / tw))
+		maxY := int(math.Ceil((view.Bottom()-layer.OffsetY)/th)) + layer.overflowRows
+
+		minX = mathf.ClampInt(minX, 0, layer.Width)
+		minY = mathf.ClampInt(minY, 0, layer.Height)
+		maxX = mathf.ClampInt(maxX, 0, layer.Width)
+		maxY = mathf.ClampInt(maxY, 0, layer.Height)
+
+		for y := minY; y < maxY; y++ {
+			for x := minX; x < maxX; x++ {
+				tile := layer.Tiles[y*layer.Width+x]
+
+				sprite := tile.Sprite()
+				if sprite == nil {
+					continue
+				}
+
+				sw, sh := sprite.NativeSize()
+				drawnHeight := sh
+
+				if tile.FlipD {
+					drawnHeight = sw
+				}
+
+				opts.GeoM = sprite.RegionGeoM()
+				opts.GeoM.Concat(tile.FlipGeoM(sw, sh))
+				opts.GeoM.Translate(
+					layer.OffsetX+float64(x)*tw,
+					layer.OffsetY+float64(y+1)*th-drawnHeight,
+				)
+				opts.GeoM.Concat(geom)
+
+				opts.ColorM = sprite.ColorM
+				opts.ColorM.Scale(1, 1, 1, layer.Opacity)
+				opts.Filter = sprite.Filter
+				opts.CompositeMode = sprite.CompositeMode
+
+				dest.DrawImage(sprite.Image, opts)
+			}
+		}
+	}
+}
+
+// localView returns the cull bounds in our local space, the bounding box
+// is used when rotated so some tiles outside of the view are still drawn.
+// The cull transform is in world space while the destination is in screen space.
+func (v *TilemapVisual) localView(dest *ebiten.Image) (mathf.Bounds, bool) {
+	var view mathf.Bounds
+
+	geom := v.Transform.GeoM()
+
+	if v.cull != nil {
+		view = v.cull.Bounds()
+		geom = v.Transform.WorldGeoM()
+	} else {
+		size := dest.Bounds()
+		view = mathf.NewBoundsWidthHeight(
+			float64(size.Min.X),
+			float64(size.Min.Y),
+			float64(size.Dx()),
+			float64(size.Dy()),
+		)
+	}
+
+	if !geom.IsInvertible() {
+		return view, false
+	}
+
+	geom.Invert()
+
+	return mathf.TransformBounds(view, geom), true
+}
+
+func newTilemapLayer(layer *content.TileLayer) tilemapLayer {
+	tl := tilemapLayer{TileLayer: layer}
+	if layer.TileWidth <= 0 || layer.TileHeight <= 0 {
+		return tl
+	}
+
+	seen := make(map[*content.Tileset]bool)
+
+	for _, tile := range layer.Tiles {
+		if tile.Tileset == nil || seen[tile.Tileset] {
+			continue
+		}
+
+		seen[tile.Tileset] = true
+
+		size := tile.Tileset.TileWidth
+		if tile.Tileset.TileHeight > size {
+			size = tile.Tileset.TileHeight
+		}
+
+		// diagonal flips swap width and height so we use the larger of the two
+		if cols := (size - 1) / layer.TileWidth; cols > tl.overflowCols {
+			tl.overflowCols = cols
+		}
+
+		if rows := (size - 1) / layer.TileHeight; rows > tl.overflowRows {
+			tl.overflowRows = rows
+		}
+	}
+
+	return tl
+}
@@ -0,0 +1,179 @@
+package graphics_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/graphics"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// newTileLayer creates a layer of 2x2 cells with a single tile placed
+func newTileLayer(
+	tileset *content.Tileset,
+	width, height int,
+	placed image.Point,
+	tile content.Tile,
+) *content.TileLayer {
+	layer := &content.TileLayer{
+		Width:      width,
+		Height:     height,
+		TileWidth:  2,
+		TileHeight: 2,
+		Opacity:    1,
+		Visible:    true,
+		Tiles:      make([]content.Tile, width*height),
+	}
+
+	tile.Tileset = tileset
+	layer.Tiles[placed.Y*width+placed.X] = tile
+
+	return layer
+}
+
+func TestTilemapVisualDrawFlips(t *testing.T) {
+	tileset := &content.Tileset{
+		TileWidth:  2,
+		TileHeight: 2,
+		Sprites:    []*content.Sprite{newGridSprite([][]uint8{{1, 2}, {3, 4}})},
+	}
+
+	tests := map[string]struct {
+		tile content.Tile
+		// red values of the tile from left to right, top to bottom
+		expected [4]uint8
+	}{
+		"no flip": {
+			expected: [4]uint8{1, 2, 3, 4},
+		},
+		"horizontal": {
+			tile:     content.Tile{FlipH: true},
+			expected: [4]uint8{2, 1, 4, 3},
+		},
+		"vertical": {
+			tile:     content.Tile{FlipV: true},
+			expected: [4]uint8{3, 4, 1, 2},
+		},
+		"diagonal": {
+			tile:     content.Tile{FlipD: true},
+			expected: [4]uint8{1, 3, 2, 4},
+		},
+		"horizontal and vertical": {
+			tile:     content.Tile{FlipH: true, FlipV: true},
+			expected: [4]uint8{4, 3, 2, 1},
+		},
+		"diagonal and horizontal": {
+			tile:     content.Tile{FlipD: true, FlipH: true},
+			expected: [4]uint8{3, 1, 4, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := mathf.NewTransform()
+			root.SetSize(8, 8)
+			root.SetNaturalWidth(8)
+			root.SetNaturalHeight(8)
+			root.Build(nil)
+
+			v := graphics.NewTilemapVisual()
+			v.SetLayers(newTileLayer(tileset, 2, 2, image.Pt(1, 1), tc.tile))
+			v.SetVisible(true)
+			v.Layout(root, root)
+
+			dest := ebiten.NewImage(8, 8)
+			v.Visualer.Draw(dest)
+
+			for i, red := range tc.expected {
+				x, y := 2+i%2, 2+i/2
+
+				if got := pixelAt(t, dest, x, y); got.R != red || got.A == 0 {
+					t.Fatalf("expected %v, %v: %v, got: %v", x, y, red, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTilemapVisualDrawCulling(t *testing.T) {
+	// a 4x4 tile on 2x2 cells overflows one cell up and to the right
+	grid := make([][]uint8, 4)
+	for y := range grid {
+		grid[y] = []uint8{uint8(1 + y*4), uint8(2 + y*4), uint8(3 + y*4), uint8(4 + y*4)}
+	}
+
+	tileset := &content.Tileset{
+		TileWidth:  4,
+		TileHeight: 4,
+		Sprites:    []*content.Sprite{newGridSprite(grid)},
+	}
+
+	tests := map[string]struct {
+		placed   image.Point
+		position mathf.Vec2
+		// cull size, zero culls to the destination image
+		cull float64
+		// red values at each point, zero expects a transparent pixel
+		expected map[image.Point]uint8
+	}{
+		"tile left of the view": {
+			placed:   image.Pt(0, 1),
+			position: mathf.Vec2{X: -3},
+			expected: map[image.Point]uint8{{0, 0}: 4, {0, 3}: 16, {1, 0}: 0},
+		},
+		"tile below the view": {
+			placed:   image.Pt(0, 2),
+			cull:     4,
+			expected: map[image.Point]uint8{{0, 2}: 1, {3, 3}: 8},
+		},
+		"tile outside of the cull transform": {
+			placed:   image.Pt(3, 3),
+			cull:     4,
+			expected: map[image.Point]uint8{{7, 5}: 0},
+		},
+		"tile inside of the cull transform": {
+			placed:   image.Pt(0, 1),
+			cull:     4,
+			expected: map[image.Point]uint8{{0, 0}: 1, {3, 3}: 16},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := mathf.NewTransform()
+			root.SetSize(16, 16)
+			root.SetNaturalWidth(16)
+			root.SetNaturalHeight(16)
+			root.Build(nil)
+
+			v := graphics.NewTilemapVisual()
+			v.SetLayers(newTileLayer(tileset, 4, 4, tc.placed, content.Tile{}))
+			v.Transform.SetPosition(tc.position)
+			v.SetVisible(true)
+
+			if tc.cull > 0 {
+				cull := mathf.NewTransform()
+				cull.SetSize(tc.cull, tc.cull)
+				cull.SetNaturalWidth(tc.cull)
+				cull.SetNaturalHeight(tc.cull)
+				cull.Build(nil)
+				v.SetCullTransform(cull)
+			}
+
+			v.Layout(root, root)
+
+			dest := ebiten.NewImage(16, 16)
+			v.Visualer.Draw(dest)
+
+			for point, red := range tc.expected {
+				got := pixelAt(t, dest, point.X, point.Y)
+				if got.R != red || (got.A == 0) != (red == 0) {
+					t.Fatalf("expected %v: %v, got: %v", point, red, got)
+				}
+			}
+		})
+	}
+}
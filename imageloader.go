@@ -0,0 +1,181 @@
+package igloo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+)
+
+// ImageOptions changes how images are decoded before being uploaded
+type ImageOptions struct {
+	// ColorKey pixels matching this RGB value will be made transparent
+	ColorKey *color.RGBA
+	// Premultiplied treats the decoded pixels as already premultiplied by alpha,
+	// for art exported with premultiplied colors in a straight alpha format.
+	Premultiplied bool
+}
+
+type ImageOption func(o *ImageOptions)
+
+// ImageWithColorKey makes every pixel matching the RGB of key transparent
+func ImageWithColorKey(key color.Color) ImageOption {
+	return func(o *ImageOptions) {
+		r, g, b, _ := key.RGBA()
+		o.ColorKey = &color.RGBA{
+			R: uint8(r >> 8),
+			G: uint8(g >> 8),
+			B: uint8(b >> 8),
+			A: 0xff,
+		}
+	}
+}
+
+// ImageWithPremultipliedAlpha treats decoded colors as already premultiplied
+func ImageWithPremultipliedAlpha() ImageOption {
+	return func(o *ImageOptions) {
+		o.Premultiplied = true
+	}
+}
+
+func newImageOptions(options []ImageOption) ImageOptions {
+	var opts ImageOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return opts
+}
+
+// apply returns img with our options applied, img is returned
+// untouched if there are no options to apply.
+func (o ImageOptions) apply(img image.Image) image.Image {
+	if o.ColorKey == nil && !o.Premultiplied {
+		return img
+	}
+
+	if !o.Premultiplied {
+		// copy the image so we never modify decoded or cached pixels
+		nrgba := image.NewNRGBA(img.Bounds())
+		draw.Draw(nrgba, nrgba.Rect, img, img.Bounds().Min, draw.Src)
+		o.applyColorKey(nrgba.Pix)
+
+		return nrgba
+	}
+
+	model := img.ColorModel()
+	if model != color.NRGBAModel && model != color.NRGBA64Model {
+		// our colors are already premultiplied, converting them to straight
+		// alpha and back would divide them by alpha a second time
+		rgba := image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Rect, img, img.Bounds().Min, draw.Src)
+		o.applyColorKey(rgba.Pix)
+
+		return rgba
+	}
+
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || o.ColorKey != nil {
+		nrgba = image.NewNRGBA(img.Bounds())
+		draw.Draw(nrgba, nrgba.Rect, img, img.Bounds().Min, draw.Src)
+		o.applyColorKey(nrgba.Pix)
+	}
+
+	// same memory layout, only the interpretation of the color changes
+	return &image.RGBA{
+		Pix:    nrgba.Pix,
+		Stride: nrgba.Stride,
+		Rect:   nrgba.Rect,
+	}
+}
+
+// applyColorKey clears every pixel matching our color key
+func (o ImageOptions) applyColorKey(pix []uint8) {
+	if o.ColorKey == nil {
+		return
+	}
+
+	key := o.ColorKey
+
+	for i := 0; i < len(pix); i += 4 {
+		if pix[i] == key.R && pix[i+1] == key.G && pix[i+2] == key.B {
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = 0, 0, 0, 0
+		}
+	}
+}
+
+// LoadGIF loads every frame of an animated gif, frames are composited
+// following their disposal methods so each sprite is a complete image.
+func (a *AssetLoader) LoadGIF(path string, options ...ImageOption) (*content.Animation, error) {
+	opts := newImageOptions(options)
+	fullPath, err := a.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := a.fsys.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("decoding gif %v: %w", fullPath, err)
+	}
+
+	bounds := image.Rect(0, 0, decoded.Config.Width, decoded.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	previous := image.NewRGBA(bounds)
+	anim := &content.Animation{
+		Name:      path,
+		Direction: content.AnimationForward,
+		Frames:    make([]content.Frame, len(decoded.Image)),
+	}
+
+	for i, frame := range decoded.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(decoded.Disposal) {
+			disposal = decoded.Disposal[i]
+		}
+
+		if disposal == gif.DisposalPrevious {
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		snapshot := image.NewRGBA(bounds)
+		copy(snapshot.Pix, canvas.Pix)
+
+		delay := 0
+		if i < len(decoded.Delay) {
+			delay = decoded.Delay[i]
+		}
+
+		anim.Frames[i] = content.Frame{
+			Sprite: &content.Sprite{
+				Image: ebiten.NewImageFromImage(opts.apply(snapshot)),
+				Name:  fmt.Sprintf("%v %v", path, i),
+			},
+			// gif delays are in hundredths of a second
+			Duration: float64(delay) / 100,
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+
+	return anim, nil
+}
@@ -0,0 +1,191 @@
+package igloo_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/miniscruff/igloo"
+)
+
+var (
+	magenta = color.NRGBA{R: 0xff, B: 0xff, A: 0xff}
+	red     = color.RGBA{R: 0xff, A: 0xff}
+	blue    = color.RGBA{B: 0xff, A: 0xff}
+)
+
+// encodePNG encodes a magenta pixel followed by a half transparent pixel
+func encodePNG(t *testing.T, img interface {
+	image.Image
+	Set(x, y int, c color.Color)
+}) []byte {
+	var buf bytes.Buffer
+
+	img.Set(0, 0, magenta)
+	img.Set(1, 0, color.NRGBA{R: 100, G: 50, B: 24, A: 128})
+
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestLoadImageOptions(t *testing.T) {
+	rect := image.Rect(0, 0, 2, 1)
+	fsys := fstest.MapFS{
+		"nrgba.png":   {Data: encodePNG(t, image.NewNRGBA(rect))},
+		"nrgba64.png": {Data: encodePNG(t, image.NewNRGBA64(rect))},
+	}
+
+	straight := color.RGBA{R: 50, G: 25, B: 12, A: 128}
+	premultiplied := color.RGBA{R: 100, G: 50, B: 24, A: 128}
+
+	tests := map[string]struct {
+		path     string
+		options  []igloo.ImageOption
+		expected [2]color.RGBA
+	}{
+		"no options": {
+			path:     "nrgba.png",
+			expected: [2]color.RGBA{{R: 0xff, B: 0xff, A: 0xff}, straight},
+		},
+		"color key": {
+			path:     "nrgba.png",
+			options:  []igloo.ImageOption{igloo.ImageWithColorKey(magenta)},
+			expected: [2]color.RGBA{{}, straight},
+		},
+		"premultiplied": {
+			path:     "nrgba.png",
+			options:  []igloo.ImageOption{igloo.ImageWithPremultipliedAlpha()},
+			expected: [2]color.RGBA{{R: 0xff, B: 0xff, A: 0xff}, premultiplied},
+		},
+		"premultiplied with color key": {
+			path: "nrgba.png",
+			options: []igloo.ImageOption{
+				igloo.ImageWithColorKey(magenta),
+				igloo.ImageWithPremultipliedAlpha(),
+			},
+			expected: [2]color.RGBA{{}, premultiplied},
+		},
+		"premultiplied deep color": {
+			path:     "nrgba64.png",
+			options:  []igloo.ImageOption{igloo.ImageWithPremultipliedAlpha()},
+			expected: [2]color.RGBA{{R: 0xff, B: 0xff, A: 0xff}, premultiplied},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			img, err := igloo.NewAssetLoader(fsys, ".").LoadImage(tc.path, tc.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for x, expected := range tc.expected {
+				if got := pixelAt(t, img, x, 0); !closeColor(got, expected) {
+					t.Fatalf("expected %v: %v, got: %v", x, expected, got)
+				}
+			}
+		})
+	}
+}
+
+// closeColor allows for rounding when colors are premultiplied
+func closeColor(a, b color.RGBA) bool {
+	near := func(x, y uint8) bool {
+		return x-y <= 1 || y-x <= 1
+	}
+
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && a.A == b.A
+}
+
+// encodeGIF encodes a red 2x1 frame, a blue pixel on the right and
+// an empty pixel on the left using disposals for each frame.
+func encodeGIF(t *testing.T, disposals []byte) []byte {
+	palette := color.Palette{color.RGBA{}, red, blue}
+	frames := []*image.Paletted{
+		image.NewPaletted(image.Rect(0, 0, 2, 1), palette),
+		image.NewPaletted(image.Rect(1, 0, 2, 1), palette),
+		image.NewPaletted(image.Rect(0, 0, 1, 1), palette),
+	}
+
+	frames[0].SetColorIndex(0, 0, 1)
+	frames[0].SetColorIndex(1, 0, 1)
+	frames[1].SetColorIndex(1, 0, 2)
+
+	var buf bytes.Buffer
+
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:    frames,
+		Delay:    []int{10, 20, 30},
+		Disposal: disposals,
+		Config:   image.Config{ColorModel: palette, Width: 2, Height: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestLoadGIF(t *testing.T) {
+	none := byte(gif.DisposalNone)
+
+	tests := map[string]struct {
+		disposals []byte
+		options   []igloo.ImageOption
+		expected  [2]color.RGBA
+	}{
+		"none": {
+			disposals: []byte{none, none, none},
+			expected:  [2]color.RGBA{red, blue},
+		},
+		"premultiplied": {
+			disposals: []byte{none, none, none},
+			options:   []igloo.ImageOption{igloo.ImageWithPremultipliedAlpha()},
+			expected:  [2]color.RGBA{red, blue},
+		},
+		"background after the first frame": {
+			disposals: []byte{gif.DisposalBackground, none, none},
+			expected:  [2]color.RGBA{{}, blue},
+		},
+		"background after the second frame": {
+			disposals: []byte{none, gif.DisposalBackground, none},
+			expected:  [2]color.RGBA{red, {}},
+		},
+		"previous after the second frame": {
+			disposals: []byte{none, gif.DisposalPrevious, none},
+			expected:  [2]color.RGBA{red, red},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"anim.gif": {Data: encodeGIF(t, tc.disposals)},
+			}
+
+			anim, err := igloo.NewAssetLoader(fsys, ".").LoadGIF("anim.gif", tc.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(anim.Frames) != 3 || anim.Frames[2].Duration != 0.3 {
+				t.Fatalf("expected: 3 frames lasting 0.3 at the end, got: %+v", anim.Frames)
+			}
+
+			last := anim.Frames[2].Sprite.Image
+
+			for x, expected := range tc.expected {
+				if got := pixelAt(t, last, x, 0); got != expected {
+					t.Fatalf("expected %v: %v, got: %v", x, expected, got)
+				}
+			}
+		})
+	}
+}
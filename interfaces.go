@@ -2,6 +2,8 @@ package igloo
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/mathf"
 )
 
 // Dirtier allows structs to track when they are changed and only apply
@@ -28,6 +30,27 @@ type Drawer interface {
 	Draw(dest *ebiten.Image)
 }
 
+// ChildDrawer is an optional interface for drawers that draw their own
+// children, such as containers drawing their children offscreen.
+// Visuals with a ChildDrawer are drawn instead of their children.
+type ChildDrawer interface {
+	DrawWithChildren(dest *ebiten.Image)
+}
+
+// HitFilter is an optional interface for drawers that limit where they and
+// their children can be hit, such as clipping containers.
+type HitFilter interface {
+	AllowsHit(point mathf.Vec2) bool
+}
+
+// BoundsWatcher is an optional interface for dirtiers whose native size
+// depends on the bounds they are built with, such as wrapped text.
+// BoundsBuilt is called after our transform is built, returning true
+// updates our native size and builds our transform again.
+type BoundsWatcher interface {
+	BoundsBuilt(bounds mathf.Bounds) bool
+}
+
 type NativeSizer interface {
 	NativeSize() (float64, float64)
 }
@@ -48,6 +71,18 @@ type PostSetup interface {
 	PostSetup() error
 }
 
+// PostPass is a single post processing step, it reads src and draws over
+// dest without clearing it. Both images are the same size.
+type PostPass interface {
+	Apply(dest, src *ebiten.Image)
+}
+
+// PostProcessor is an optional interface for scenes that run their own
+// post processing chain over everything they draw, return nil to skip it.
+type PostProcessor interface {
+	PostChain() *PostChain
+}
+
 // PreDispose is an optional interface for scenes that will trigger before the
 // normal dispose when a scene is popped.
 type PreDispose interface {
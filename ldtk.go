@@ -0,0 +1,674 @@
+package igloo
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// LDtk flips tiles using the first two bits of a tile's flags
+const (
+	ldtkFlipX = 1 << iota
+	ldtkFlipY
+)
+
+type ldtkCustomData struct {
+	TileID int    `json:"tileId"`
+	Data   string `json:"data"`
+}
+
+type ldtkTilesetDef struct {
+	UID          int              `json:"uid"`
+	Identifier   string           `json:"identifier"`
+	RelPath      string           `json:"relPath"`
+	TileGridSize int              `json:"tileGridSize"`
+	Spacing      int              `json:"spacing"`
+	Padding      int              `json:"padding"`
+	CWid         int              `json:"__cWid"`
+	CHei         int              `json:"__cHei"`
+	CustomData   []ldtkCustomData `json:"customData"`
+}
+
+type ldtkDefs struct {
+	Tilesets []ldtkTilesetDef `json:"tilesets"`
+}
+
+type ldtkField struct {
+	Identifier string          `json:"__identifier"`
+	Type       string          `json:"__type"`
+	Value      json.RawMessage `json:"__value"`
+}
+
+type ldtkTileRect struct {
+	TilesetUID int `json:"tilesetUid"`
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	W          int `json:"w"`
+	H          int `json:"h"`
+}
+
+type ldtkPoint struct {
+	CX int `json:"cx"`
+	CY int `json:"cy"`
+}
+
+type ldtkEntityRef struct {
+	EntityIID string `json:"entityIid"`
+	LayerIID  string `json:"layerIid"`
+	LevelIID  string `json:"levelIid"`
+	WorldIID  string `json:"worldIid"`
+}
+
+type ldtkTile struct {
+	Px [2]float64 `json:"px"`
+	T  int        `json:"t"`
+	F  int        `json:"f"`
+}
+
+type ldtkEntity struct {
+	Identifier     string        `json:"__identifier"`
+	IID            string        `json:"iid"`
+	Grid           [2]int        `json:"__grid"`
+	Pivot          [2]float64    `json:"__pivot"`
+	Tags           []string      `json:"__tags"`
+	Tile           *ldtkTileRect `json:"__tile"`
+	Px             [2]float64    `json:"px"`
+	Width          float64       `json:"width"`
+	Height         float64       `json:"height"`
+	FieldInstances []ldtkField   `json:"fieldInstances"`
+}
+
+type ldtkLayer struct {
+	Identifier      string       `json:"__identifier"`
+	Type            string       `json:"__type"`
+	CWid            int          `json:"__cWid"`
+	CHei            int          `json:"__cHei"`
+	GridSize        int          `json:"__gridSize"`
+	Opacity         float64      `json:"__opacity"`
+	OffsetX         float64      `json:"__pxTotalOffsetX"`
+	OffsetY         float64      `json:"__pxTotalOffsetY"`
+	TilesetDefUID   *int         `json:"__tilesetDefUid"`
+	Visible         bool         `json:"visible"`
+	IntGridCSV      []int        `json:"intGridCsv"`
+	AutoLayerTiles  []ldtkTile   `json:"autoLayerTiles"`
+	GridTiles       []ldtkTile   `json:"gridTiles"`
+	EntityInstances []ldtkEntity `json:"entityInstances"`
+}
+
+// ldtkLevelHeader is everything about a level but its layers, so
+// projects only keep the headers of levels that are not loaded.
+type ldtkLevelHeader struct {
+	Identifier      string      `json:"identifier"`
+	IID             string      `json:"iid"`
+	WorldX          int         `json:"worldX"`
+	WorldY          int         `json:"worldY"`
+	PxWid           int         `json:"pxWid"`
+	PxHei           int         `json:"pxHei"`
+	FieldInstances  []ldtkField `json:"fieldInstances"`
+	ExternalRelPath string      `json:"externalRelPath"`
+
+	// where the level is stored in the project file,
+	// a world of -1 are levels at the root of the project
+	world int
+	index int
+}
+
+func (l ldtkLevelHeader) info() content.LevelInfo {
+	return content.LevelInfo{
+		Name:   l.Identifier,
+		IID:    l.IID,
+		WorldX: l.WorldX,
+		WorldY: l.WorldY,
+		Width:  l.PxWid,
+		Height: l.PxHei,
+	}
+}
+
+type ldtkLevel struct {
+	ldtkLevelHeader
+	LayerInstances []ldtkLayer `json:"layerInstances"`
+}
+
+// ldtkLevelKey finds a level by its world and identifier
+// as identifiers are only unique inside of a world
+type ldtkLevelKey struct {
+	world string
+	name  string
+}
+
+type ldtkWorld struct {
+	Identifier  string            `json:"identifier"`
+	IID         string            `json:"iid"`
+	WorldLayout string            `json:"worldLayout"`
+	Levels      []ldtkLevelHeader `json:"levels"`
+}
+
+type ldtkFile struct {
+	IID         string            `json:"iid"`
+	WorldLayout string            `json:"worldLayout"`
+	Defs        ldtkDefs          `json:"defs"`
+	Levels      []ldtkLevelHeader `json:"levels"`
+	Worlds      []ldtkWorld       `json:"worlds"`
+}
+
+// ldtkLevelsFile only reads the levels of a project without decoding them
+type ldtkLevelsFile struct {
+	Levels []json.RawMessage `json:"levels"`
+	Worlds []struct {
+		Levels []json.RawMessage `json:"levels"`
+	} `json:"worlds"`
+}
+
+// LDtkProject is a loaded LDtk project.
+// Only level headers are kept until a level is loaded with LoadLevel,
+// the layers of a level are read from the project or level file then.
+type LDtkProject struct {
+	loader      *AssetLoader
+	path        string
+	dir         string
+	worlds      []*content.World
+	levels      map[string]ldtkLevelHeader
+	levelIIDs   map[ldtkLevelKey]string
+	tilesetDefs map[int]ldtkTilesetDef
+	tilesets    map[int]*content.Tileset
+}
+
+// LoadLDtk loads an LDtk project, the worlds and level infos are available
+// right away with each level loaded separately using LoadLevel.
+func (a *AssetLoader) LoadLDtk(projectPath string) (*LDtkProject, error) {
+	projectBytes, err := a.readFSFile(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var file ldtkFile
+
+	if err = json.Unmarshal(projectBytes, &file); err != nil {
+		return nil, fmt.Errorf("loading ldtk project %v: %w", projectPath, err)
+	}
+
+	p := &LDtkProject{
+		loader:      a,
+		path:        projectPath,
+		dir:         path.Dir(projectPath),
+		levels:      make(map[string]ldtkLevelHeader),
+		levelIIDs:   make(map[ldtkLevelKey]string),
+		tilesetDefs: make(map[int]ldtkTilesetDef),
+		tilesets:    make(map[int]*content.Tileset),
+	}
+
+	for _, def := range file.Defs.Tilesets {
+		p.tilesetDefs[def.UID] = def
+	}
+
+	// projects without multiple worlds store levels at the root
+	if len(file.Worlds) == 0 {
+		p.addWorld(-1, ldtkWorld{
+			Identifier:  "World",
+			IID:         file.IID,
+			WorldLayout: file.WorldLayout,
+			Levels:      file.Levels,
+		})
+
+		return p, nil
+	}
+
+	for i, w := range file.Worlds {
+		p.addWorld(i, w)
+	}
+
+	return p, nil
+}
+
+func (p *LDtkProject) addWorld(index int, w ldtkWorld) {
+	world := &content.World{
+		Name:   w.Identifier,
+		IID:    w.IID,
+		Layout: w.WorldLayout,
+		Levels: make([]content.LevelInfo, len(w.Levels)),
+	}
+
+	for i, level := range w.Levels {
+		level.world = index
+		level.index = i
+
+		world.Levels[i] = level.info()
+		p.levels[level.IID] = level
+		p.levelIIDs[ldtkLevelKey{world: w.Identifier, name: level.Identifier}] = level.IID
+	}
+
+	p.worlds = append(p.worlds, world)
+}
+
+// Worlds returns every world of the project, there is only one
+// world unless multiple worlds are enabled in the project.
+func (p *LDtkProject) Worlds() []*content.World {
+	return p.worlds
+}
+
+// LoadLevel loads a level by its identifier or IID along with any
+// tilesets it uses. Tilesets are shared between levels of a project.
+// Tile layers where tiles are stacked are split into multiple tile
+// layers with the same name.
+// Identifiers used in more than one world are an error,
+// use the IID or LoadWorldLevel for those.
+func (p *LDtkProject) LoadLevel(name string) (*content.Level, error) {
+	if _, ok := p.levels[name]; ok {
+		return p.loadLevel(name)
+	}
+
+	iid := ""
+
+	for key, levelIID := range p.levelIIDs {
+		if key.name != name {
+			continue
+		}
+
+		if iid != "" {
+			return nil, fmt.Errorf("loading level %v: found in multiple worlds", name)
+		}
+
+		iid = levelIID
+	}
+
+	if iid == "" {
+		return nil, fmt.Errorf("loading level %v: not found", name)
+	}
+
+	return p.loadLevel(iid)
+}
+
+// LoadWorldLevel loads a level by the identifier of its world and its own
+// identifier, the same as LoadLevel.
+func (p *LDtkProject) LoadWorldLevel(world, name string) (*content.Level, error) {
+	iid, ok := p.levelIIDs[ldtkLevelKey{world: world, name: name}]
+	if !ok {
+		return nil, fmt.Errorf("loading level %v in world %v: not found", name, world)
+	}
+
+	return p.loadLevel(iid)
+}
+
+func (p *LDtkProject) loadLevel(iid string) (*content.Level, error) {
+	header := p.levels[iid]
+
+	level, err := p.readLevel(header)
+	if err != nil {
+		return nil, fmt.Errorf("loading level %v: %w", header.Identifier, err)
+	}
+
+	loaded, err := p.buildLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("loading level %v: %w", header.Identifier, err)
+	}
+
+	return loaded, nil
+}
+
+// readLevel decodes a whole level from its own file, or for levels
+// embedded in the project, from the project file again.
+func (p *LDtkProject) readLevel(header ldtkLevelHeader) (ldtkLevel, error) {
+	var level ldtkLevel
+
+	if header.ExternalRelPath != "" {
+		levelBytes, err := p.loader.readFSFile(path.Join(p.dir, header.ExternalRelPath))
+		if err != nil {
+			return level, err
+		}
+
+		err = json.Unmarshal(levelBytes, &level)
+
+		return level, err
+	}
+
+	projectBytes, err := p.loader.readFSFile(p.path)
+	if err != nil {
+		return level, err
+	}
+
+	var file ldtkLevelsFile
+
+	if err = json.Unmarshal(projectBytes, &file); err != nil {
+		return level, err
+	}
+
+	levels := file.Levels
+	if header.world >= 0 && header.world < len(file.Worlds) {
+		levels = file.Worlds[header.world].Levels
+	}
+
+	if header.index >= len(levels) {
+		return level, fmt.Errorf("project %v changed since it was loaded", p.path)
+	}
+
+	err = json.Unmarshal(levels[header.index], &level)
+
+	return level, err
+}
+
+func (p *LDtkProject) buildLevel(level ldtkLevel) (*content.Level, error) {
+	fields, err := p.fields(level.FieldInstances)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &content.Level{
+		LevelInfo: level.info(),
+		Fields:    fields,
+	}
+
+	layers := level.LayerInstances
+
+	// layer instances are stored from the top most layer down
+	for i := len(layers) - 1; i >= 0; i-- {
+		if err = p.addLayer(loaded, layers[i]); err != nil {
+			return nil, fmt.Errorf("layer %v: %w", layers[i].Identifier, err)
+		}
+	}
+
+	return loaded, nil
+}
+
+func (p *LDtkProject) addLayer(level *content.Level, layer ldtkLayer) error {
+	switch layer.Type {
+	case "IntGrid":
+		level.IntGridLayers = append(level.IntGridLayers, &content.IntGridLayer{
+			Name:     layer.Identifier,
+			Width:    layer.CWid,
+			Height:   layer.CHei,
+			GridSize: layer.GridSize,
+			OffsetX:  layer.OffsetX,
+			OffsetY:  layer.OffsetY,
+			Values:   layer.IntGridCSV,
+		})
+
+		return p.addTileLayers(level, layer, layer.AutoLayerTiles)
+	case "AutoLayer":
+		return p.addTileLayers(level, layer, layer.AutoLayerTiles)
+	case "Tiles":
+		return p.addTileLayers(level, layer, layer.GridTiles)
+	case "Entities":
+		return p.addEntityLayer(level, layer)
+	default:
+		return nil
+	}
+}
+
+// addTileLayers adds the tiles of a layer in order, tiles placed on an
+// already filled cell are moved to the next tile layer up.
+func (p *LDtkProject) addTileLayers(
+	level *content.Level,
+	layer ldtkLayer,
+	tiles []ldtkTile,
+) error {
+	if len(tiles) == 0 || layer.TilesetDefUID == nil || layer.GridSize <= 0 {
+		return nil
+	}
+
+	tileset, err := p.tileset(*layer.TilesetDefUID)
+	if err != nil {
+		return err
+	}
+
+	var stack []*content.TileLayer
+
+	for _, t := range tiles {
+		cx := int(t.Px[0]) / layer.GridSize
+		cy := int(t.Px[1]) / layer.GridSize
+
+		if cx < 0 || cy < 0 || cx >= layer.CWid || cy >= layer.CHei {
+			continue
+		}
+
+		tile := content.Tile{
+			Tileset: tileset,
+			ID:      t.T,
+			FlipH:   t.F&ldtkFlipX != 0,
+			FlipV:   t.F&ldtkFlipY != 0,
+		}
+		index := cy*layer.CWid + cx
+		placed := false
+
+		for _, tl := range stack {
+			if tl.Tiles[index].Empty() {
+				tl.Tiles[index] = tile
+				placed = true
+
+				break
+			}
+		}
+
+		if !placed {
+			tl := newLDtkTileLayer(layer)
+			tl.Tiles[index] = tile
+			stack = append(stack, tl)
+		}
+	}
+
+	level.TileLayers = append(level.TileLayers, stack...)
+
+	return nil
+}
+
+func newLDtkTileLayer(layer ldtkLayer) *content.TileLayer {
+	return &content.TileLayer{
+		Name:       layer.Identifier,
+		Width:      layer.CWid,
+		Height:     layer.CHei,
+		TileWidth:  layer.GridSize,
+		TileHeight: layer.GridSize,
+		OffsetX:    layer.OffsetX,
+		OffsetY:    layer.OffsetY,
+		Opacity:    layer.Opacity,
+		Visible:    layer.Visible,
+		Tiles:      make([]content.Tile, layer.CWid*layer.CHei),
+	}
+}
+
+func (p *LDtkProject) addEntityLayer(level *content.Level, layer ldtkLayer) error {
+	entities := &content.EntityLayer{
+		Name:     layer.Identifier,
+		OffsetX:  layer.OffsetX,
+		OffsetY:  layer.OffsetY,
+		Visible:  layer.Visible,
+		Entities: make([]*content.Entity, len(layer.EntityInstances)),
+	}
+
+	for i, e := range layer.EntityInstances {
+		fields, err := p.fields(e.FieldInstances)
+		if err != nil {
+			return fmt.Errorf("entity %v: %w", e.IID, err)
+		}
+
+		entity := &content.Entity{
+			Name:   e.Identifier,
+			IID:    e.IID,
+			X:      e.Px[0],
+			Y:      e.Px[1],
+			Width:  e.Width,
+			Height: e.Height,
+			Pivot:  mathf.Vec2{X: e.Pivot[0], Y: e.Pivot[1]},
+			Grid:   image.Pt(e.Grid[0], e.Grid[1]),
+			Tags:   e.Tags,
+			Fields: fields,
+		}
+
+		if e.Tile != nil {
+			if entity.Sprite, err = p.tileSprite(*e.Tile); err != nil {
+				return fmt.Errorf("entity %v: %w", e.IID, err)
+			}
+		}
+
+		entities.Entities[i] = entity
+	}
+
+	level.EntityLayers = append(level.EntityLayers, entities)
+
+	return nil
+}
+
+// tileset returns a tileset by uid, loading its image the first time
+func (p *LDtkProject) tileset(uid int) (*content.Tileset, error) {
+	if tileset, ok := p.tilesets[uid]; ok {
+		return tileset, nil
+	}
+
+	def, ok := p.tilesetDefs[uid]
+	if !ok || def.RelPath == "" {
+		return nil, fmt.Errorf("tileset %v has no image", uid)
+	}
+
+	img, err := p.loader.LoadImage(path.Join(p.dir, def.RelPath))
+	if err != nil {
+		return nil, err
+	}
+
+	tileset := &content.Tileset{
+		Name:       def.Identifier,
+		TileWidth:  def.TileGridSize,
+		TileHeight: def.TileGridSize,
+		Sprites: gridSprites(
+			img,
+			image.Pt(def.TileGridSize, def.TileGridSize),
+			def.Padding,
+			def.Spacing,
+			def.CWid,
+			def.CWid*def.CHei,
+		),
+		Properties: make(map[int]content.Properties),
+	}
+
+	for _, data := range def.CustomData {
+		tileset.Properties[data.TileID] = content.Properties{"data": data.Data}
+	}
+
+	p.tilesets[uid] = tileset
+
+	return tileset, nil
+}
+
+// tileSprite returns a sprite of any rectangle of a tileset
+func (p *LDtkProject) tileSprite(rect ldtkTileRect) (*content.Sprite, error) {
+	tileset, err := p.tileset(rect.TilesetUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tileset.Sprites) == 0 {
+		return nil, fmt.Errorf("tileset %v is empty", rect.TilesetUID)
+	}
+
+	// every sprite of a tileset shares the same source image
+	img := tileset.Sprites[0].PageImage()
+	bounds := image.Rect(rect.X, rect.Y, rect.X+rect.W, rect.Y+rect.H)
+
+	return &content.Sprite{
+		Image: img.SubImage(bounds).(*ebiten.Image),
+		Page:  img,
+	}, nil
+}
+
+// fields converts field instances to typed values, arrays are []any.
+// Points are image.Point grid positions, colors are color.NRGBA,
+// entity references are content.EntityRef, tiles are *content.Sprite and
+// enums along with all text types are strings.
+func (p *LDtkProject) fields(instances []ldtkField) (content.Properties, error) {
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	fields := make(content.Properties, len(instances))
+
+	for _, field := range instances {
+		value, err := p.fieldValue(field.Type, field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", field.Identifier, err)
+		}
+
+		fields[field.Identifier] = value
+	}
+
+	return fields, nil
+}
+
+func (p *LDtkProject) fieldValue(fieldType string, raw json.RawMessage) (any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(fieldType, "Array<") {
+		var items []json.RawMessage
+
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+
+		itemType := strings.TrimSuffix(strings.TrimPrefix(fieldType, "Array<"), ">")
+		values := make([]any, len(items))
+
+		for i, item := range items {
+			value, err := p.fieldValue(itemType, item)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = value
+		}
+
+		return values, nil
+	}
+
+	switch fieldType {
+	case "Int":
+		var value int
+		err := json.Unmarshal(raw, &value)
+
+		return value, err
+	case "Float":
+		var value float64
+		err := json.Unmarshal(raw, &value)
+
+		return value, err
+	case "Bool":
+		var value bool
+		err := json.Unmarshal(raw, &value)
+
+		return value, err
+	case "Color":
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		return parseHexColor(value)
+	case "Point":
+		var value ldtkPoint
+		err := json.Unmarshal(raw, &value)
+
+		return image.Pt(value.CX, value.CY), err
+	case "EntityRef":
+		var value ldtkEntityRef
+		err := json.Unmarshal(raw, &value)
+
+		return content.EntityRef(value), err
+	case "Tile":
+		var value ldtkTileRect
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		return p.tileSprite(value)
+	default:
+		var value string
+		err := json.Unmarshal(raw, &value)
+
+		return value, err
+	}
+}
@@ -0,0 +1,242 @@
+package igloo_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"testing/fstest"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+)
+
+const ldtkProject = `{
+ "iid": "project", "worldLayout": "Free",
+ "defs": {"tilesets": [{"uid": 1, "identifier": "Ground", "relPath": "tiles.png",
+  "tileGridSize": 2, "spacing": 0, "padding": 0, "__cWid": 2, "__cHei": 1,
+  "customData": [{"tileId": 1, "data": "solid"}]}]},
+ "levels": [
+  {"identifier": "Start", "iid": "level-1", "worldX": 0, "worldY": 0, "pxWid": 4, "pxHei": 4,
+   "fieldInstances": [{"__identifier": "music", "__type": "String", "__value": "forest"}],
+   "layerInstances": [
+    {"__identifier": "Entities", "__type": "Entities", "__cWid": 2, "__cHei": 2,
+     "__gridSize": 2, "__opacity": 1, "__pxTotalOffsetX": 0, "__pxTotalOffsetY": 0,
+     "visible": true, "entityInstances": [
+      {"__identifier": "Player", "iid": "player-1", "__grid": [1, 0], "__pivot": [0.5, 1],
+       "__tags": ["spawn"], "px": [3, 2], "width": 2, "height": 2,
+       "__tile": {"tilesetUid": 1, "x": 0, "y": 0, "w": 2, "h": 2},
+       "fieldInstances": [
+        {"__identifier": "health", "__type": "Int", "__value": 3},
+        {"__identifier": "speed", "__type": "Float", "__value": 1.5},
+        {"__identifier": "tint", "__type": "Color", "__value": "#FF0000"},
+        {"__identifier": "target", "__type": "Point", "__value": {"cx": 1, "cy": 1}},
+        {"__identifier": "items", "__type": "Array<LocalEnum.Item>", "__value": ["Key"]},
+        {"__identifier": "door", "__type": "EntityRef", "__value": {"entityIid": "door-1",
+         "layerIid": "layer", "levelIid": "level-2", "worldIid": "project"}},
+        {"__identifier": "icon", "__type": "Tile", "__value": null}
+       ]}
+     ]},
+    {"__identifier": "Walls", "__type": "IntGrid", "__cWid": 2, "__cHei": 2,
+     "__gridSize": 2, "__opacity": 1, "__pxTotalOffsetX": 0, "__pxTotalOffsetY": 0,
+     "__tilesetDefUid": 1, "visible": true, "intGridCsv": [1, 0, 0, 2],
+     "autoLayerTiles": [
+      {"px": [0, 0], "t": 0, "f": 0},
+      {"px": [0, 0], "t": 1, "f": 1},
+      {"px": [2, 2], "t": 1, "f": 2}
+     ]}
+   ]},
+  {"identifier": "Cave", "iid": "level-2", "worldX": 4, "worldY": 0, "pxWid": 4, "pxHei": 4,
+   "externalRelPath": "levels/cave.ldtkl", "layerInstances": null}
+ ],
+ "worlds": []
+}`
+
+const ldtkCave = `{"identifier": "Cave", "iid": "level-2", "worldX": 4, "worldY": 0,
+ "pxWid": 4, "pxHei": 4, "fieldInstances": [],
+ "layerInstances": [
+  {"__identifier": "Floor", "__type": "Tiles", "__cWid": 2, "__cHei": 2,
+   "__gridSize": 2, "__opacity": 0.5, "__pxTotalOffsetX": 0, "__pxTotalOffsetY": 0,
+   "__tilesetDefUid": 1, "visible": true, "gridTiles": [{"px": [2, 0], "t": 1, "f": 0}]}
+ ]}`
+
+func TestLoadLDtk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/tiles.png":         {Data: tiledTilesPNG(t)},
+		"assets/world.ldtk":        {Data: []byte(ldtkProject)},
+		"assets/levels/cave.ldtkl": {Data: []byte(ldtkCave)},
+		"assets/broken/world.ldtk": {Data: []byte(`{"levels": [`)},
+	}
+
+	loader := igloo.NewAssetLoader(fsys, "assets")
+
+	project, err := loader.LoadLDtk("world.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worlds := project.Worlds()
+	if len(worlds) != 1 || len(worlds[0].Levels) != 2 || worlds[0].Levels[1].Name != "Cave" {
+		t.Fatalf("expected: one world with two levels, got: %+v", worlds)
+	}
+
+	if _, err := loader.LoadLDtk("broken/world.ldtk"); err == nil {
+		t.Fatal("expected error loading a broken project")
+	}
+
+	if _, err := project.LoadLevel("Missing"); err == nil {
+		t.Fatal("expected error loading a missing level")
+	}
+
+	start, err := project.LoadLevel("Start")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkLDtkStart(t, start)
+
+	cave, err := project.LoadLevel("level-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	floor := cave.TileLayersNamed("Floor")
+	if len(floor) != 1 || floor[0].Opacity != 0.5 || floor[0].At(1, 0).ID != 1 {
+		t.Fatalf("expected: floor with tile 1, got: %+v", floor)
+	}
+}
+
+func checkLDtkStart(t *testing.T, level *content.Level) {
+	if level.Fields.String("music") != "forest" {
+		t.Fatalf("expected: forest, got: %v", level.Fields["music"])
+	}
+
+	walls := level.IntGridLayer("Walls")
+	if walls == nil || walls.At(0, 0) != 1 || walls.At(1, 1) != 2 || walls.At(1, 0) != 0 {
+		t.Fatalf("expected: walls int grid, got: %+v", walls)
+	}
+
+	// the stacked tile is split into a second layer above the first
+	tiles := level.TileLayersNamed("Walls")
+	if len(tiles) != 2 {
+		t.Fatalf("expected: 2 tile layers, got: %v", len(tiles))
+	}
+
+	tile := tiles[1].At(0, 0)
+	if tile.ID != 1 || !tile.FlipH || tile.Properties().String("data") != "solid" {
+		t.Fatalf("expected: flipped solid tile, got: %+v", tile)
+	}
+
+	if tile = tiles[0].At(1, 1); tile.ID != 1 || !tile.FlipV {
+		t.Fatalf("expected: vertically flipped tile, got: %+v", tile)
+	}
+
+	players := level.Entities("Player")
+	if len(players) != 1 {
+		t.Fatalf("expected: 1 player, got: %v", len(players))
+	}
+
+	player := players[0]
+	if player.X != 3 || player.Grid != image.Pt(1, 0) || player.Sprite == nil {
+		t.Fatalf("expected: player at 3 with a sprite, got: %+v", player)
+	}
+
+	fields := map[string]any{
+		"health": 3,
+		"speed":  1.5,
+		"tint":   color.NRGBA{R: 0xff, A: 0xff},
+		"target": image.Pt(1, 1),
+		"door": content.EntityRef{
+			EntityIID: "door-1",
+			LayerIID:  "layer",
+			LevelIID:  "level-2",
+			WorldIID:  "project",
+		},
+		"icon": nil,
+	}
+
+	for name, expected := range fields {
+		if got := player.Fields[name]; got != expected {
+			t.Fatalf("expected %v: %v, got: %v", name, expected, got)
+		}
+	}
+
+	items, _ := player.Fields["items"].([]any)
+	if len(items) != 1 || items[0] != "Key" {
+		t.Fatalf("expected: [Key], got: %v", player.Fields["items"])
+	}
+}
+
+const ldtkWorlds = `{
+ "iid": "project", "defs": {"tilesets": []}, "levels": [],
+ "worlds": [
+  {"identifier": "Overworld", "iid": "world-1", "worldLayout": "Free", "levels": [
+   {"identifier": "Level_0", "iid": "level-a", "pxWid": 4, "pxHei": 4,
+    "fieldInstances": [{"__identifier": "name", "__type": "String", "__value": "a"}],
+    "layerInstances": []}
+  ]},
+  {"identifier": "Underworld", "iid": "world-2", "worldLayout": "Free", "levels": [
+   {"identifier": "Level_1", "iid": "level-b", "pxWid": 4, "pxHei": 4,
+    "fieldInstances": [{"__identifier": "name", "__type": "String", "__value": "b"}],
+    "layerInstances": []},
+   {"identifier": "Level_0", "iid": "level-c", "pxWid": 4, "pxHei": 4,
+    "fieldInstances": [{"__identifier": "name", "__type": "String", "__value": "c"}],
+    "layerInstances": []}
+  ]}
+ ]
+}`
+
+func TestLoadLDtkWorlds(t *testing.T) {
+	fsys := fstest.MapFS{
+		"world.ldtk": {Data: []byte(ldtkWorlds)},
+	}
+
+	project, err := igloo.NewAssetLoader(fsys, ".").LoadLDtk("world.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if worlds := project.Worlds(); len(worlds) != 2 || len(worlds[1].Levels) != 2 {
+		t.Fatalf("expected: two worlds, got: %+v", worlds)
+	}
+
+	if _, err = project.LoadLevel("Level_0"); err == nil {
+		t.Fatal("expected error loading a level in multiple worlds")
+	}
+
+	if _, err = project.LoadWorldLevel("Overworld", "Level_1"); err == nil {
+		t.Fatal("expected error loading a level from the wrong world")
+	}
+
+	tests := map[string]struct {
+		load     func() (*content.Level, error)
+		expected string
+	}{
+		"by world": {
+			load: func() (*content.Level, error) {
+				return project.LoadWorldLevel("Underworld", "Level_0")
+			},
+			expected: "c",
+		},
+		"by iid": {
+			load:     func() (*content.Level, error) { return project.LoadLevel("level-a") },
+			expected: "a",
+		},
+		"unique identifier": {
+			load:     func() (*content.Level, error) { return project.LoadLevel("Level_1") },
+			expected: "b",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			level, err := tc.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := level.Fields.String("name"); got != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package locale
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/miniscruff/igloo"
+)
+
+// Args are named placeholder values, "{name}" in a message is replaced by
+// args["name"] and args["count"] selects the plural form.
+type Args map[string]any
+
+// CountArg is the placeholder used to select plural forms
+const CountArg = "count"
+
+// Binding keeps a setter up to date with a translated key
+type Binding struct {
+	locale *Locale
+	key    string
+	args   Args
+	setter func(string)
+}
+
+// SetArgs changes the placeholder values and updates the setter
+func (b *Binding) SetArgs(args Args) {
+	b.args = args
+	b.update()
+}
+
+// Unbind stops updating the setter when the language changes
+func (b *Binding) Unbind() {
+	b.locale.unbind(b)
+}
+
+func (b *Binding) update() {
+	b.setter(b.locale.Translate(b.key, b.args))
+}
+
+// Locale resolves keys to translated strings for the current language
+type Locale struct {
+	tables    map[string]*Table
+	language  string
+	fallbacks []string
+	bindings  []*Binding
+	changed   igloo.EventStoreOne[string]
+}
+
+// NewLocale creates a locale using language, fallbacks are tried in order
+// when a key is missing from the current language.
+func NewLocale(language string, fallbacks ...string) *Locale {
+	return &Locale{
+		tables:    make(map[string]*Table),
+		language:  language,
+		fallbacks: fallbacks,
+	}
+}
+
+// Load reads a json or po string table for language through the asset loader,
+// tables loaded for the same language are merged.
+func (l *Locale) Load(loader *igloo.AssetLoader, language, tablePath string) error {
+	data, err := loader.ReadFile(tablePath)
+	if err != nil {
+		return err
+	}
+
+	var table *Table
+
+	switch strings.ToLower(path.Ext(tablePath)) {
+	case ".json":
+		table, err = ParseJSON(language, data)
+	case ".po":
+		table, err = ParsePO(language, data)
+	default:
+		return fmt.Errorf("unknown string table format %v", tablePath)
+	}
+
+	if err != nil {
+		return fmt.Errorf("loading string table %v: %w", tablePath, err)
+	}
+
+	l.AddTable(table)
+
+	return nil
+}
+
+// AddTable adds or merges a table into its language
+func (l *Locale) AddTable(table *Table) {
+	existing, ok := l.tables[table.Language]
+	if !ok {
+		l.tables[table.Language] = table
+		return
+	}
+
+	for key, msg := range table.Messages {
+		existing.Messages[key] = msg
+	}
+}
+
+func (l *Locale) Language() string {
+	return l.language
+}
+
+// SetLanguage changes the current language and updates every binding
+func (l *Locale) SetLanguage(language string) {
+	if l.language == language {
+		return
+	}
+
+	l.language = language
+
+	for _, b := range l.bindings {
+		b.update()
+	}
+
+	l.changed.Publish(language)
+}
+
+// OnLanguageChanged subscribes to language changes
+func (l *Locale) OnLanguageChanged(fn igloo.EventHandlerOne[string]) {
+	l.changed.Subscribe(fn)
+}
+
+// Bind calls setter with the translated key now and whenever the language changes
+func (l *Locale) Bind(key string, args Args, setter func(string)) *Binding {
+	b := &Binding{
+		locale: l,
+		key:    key,
+		args:   args,
+		setter: setter,
+	}
+
+	l.bindings = append(l.bindings, b)
+	b.update()
+
+	return b
+}
+
+func (l *Locale) unbind(binding *Binding) {
+	for i, b := range l.bindings {
+		if b == binding {
+			l.bindings = append(l.bindings[:i], l.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// Has returns true if the key exists in the current language or a fallback
+func (l *Locale) Has(key string) bool {
+	_, _, ok := l.lookup(key)
+	return ok
+}
+
+// Translate resolves key in the current language, then its base language,
+// then each fallback. Missing keys are returned as is.
+func (l *Locale) Translate(key string, args Args) string {
+	msg, language, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+
+	text := msg.Text
+
+	if count, ok := countArg(args); ok && msg.Plurals != nil {
+		category := PluralRuleFor(language).Select(count)
+		if plural, ok := msg.Plurals[category]; ok {
+			text = plural
+		}
+	}
+
+	return format(text, args)
+}
+
+func (l *Locale) lookup(key string) (Message, string, bool) {
+	languages := append([]string{l.language, baseLanguage(l.language)}, l.fallbacks...)
+
+	for _, language := range languages {
+		table, ok := l.tables[language]
+		if !ok {
+			continue
+		}
+
+		if msg, ok := table.Messages[key]; ok {
+			return msg, language, true
+		}
+	}
+
+	return Message{}, "", false
+}
+
+func countArg(args Args) (int, bool) {
+	switch count := args[CountArg].(type) {
+	case int:
+		return count, true
+	case int64:
+		return int(count), true
+	case int32:
+		return int(count), true
+	case uint:
+		return int(count), true
+	case float64:
+		return int(count), true
+	default:
+		return 0, false
+	}
+}
+
+// format replaces every "{name}" placeholder with its argument,
+// unknown placeholders are left untouched.
+func format(text string, args Args) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	var sb strings.Builder
+
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+		name := text[start+1 : end]
+
+		sb.WriteString(text[:start])
+
+		if value, ok := args[name]; ok {
+			sb.WriteString(fmt.Sprint(value))
+		} else {
+			sb.WriteString(text[start : end+1])
+		}
+
+		text = text[end+1:]
+	}
+
+	sb.WriteString(text)
+
+	return sb.String()
+}
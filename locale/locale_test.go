@@ -0,0 +1,166 @@
+package locale_test
+
+import (
+	"testing"
+
+	"github.com/miniscruff/igloo/locale"
+)
+
+const englishJSON = `{
+	"greeting": "Hello {name}",
+	"quit": "Quit",
+	"apples": {"one": "{count} apple", "other": "{count} apples"}
+}`
+
+const russianPO = `# russian strings
+msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : "
+"n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "greeting"
+msgstr "Привет "
+"{name}"
+
+msgid "apples"
+msgid_plural "apples"
+msgstr[0] "{count} яблоко"
+msgstr[1] "{count} яблока"
+msgstr[2] "{count} яблок"
+
+msgctxt "menu"
+msgid "quit"
+msgstr "Выход"
+`
+
+func newTestLocale(t *testing.T) *locale.Locale {
+	t.Helper()
+
+	english, err := locale.ParseJSON("en", []byte(englishJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	russian, err := locale.ParsePO("ru", []byte(russianPO))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loc := locale.NewLocale("en-US", "en")
+	loc.AddTable(english)
+	loc.AddTable(russian)
+
+	return loc
+}
+
+func TestTranslate(t *testing.T) {
+	tests := map[string]struct {
+		language string
+		key      string
+		args     locale.Args
+		expected string
+	}{
+		"base language": {
+			language: "en-US",
+			key:      "greeting",
+			args:     locale.Args{"name": "Ada"},
+			expected: "Hello Ada",
+		},
+		"plural one": {
+			language: "en",
+			key:      "apples",
+			args:     locale.Args{"count": 1},
+			expected: "1 apple",
+		},
+		"plural other": {
+			language: "en",
+			key:      "apples",
+			args:     locale.Args{"count": 3},
+			expected: "3 apples",
+		},
+		"po continuation": {
+			language: "ru",
+			key:      "greeting",
+			args:     locale.Args{"name": "Ада"},
+			expected: "Привет Ада",
+		},
+		"po plural few": {
+			language: "ru",
+			key:      "apples",
+			args:     locale.Args{"count": 22},
+			expected: "22 яблока",
+		},
+		"po plural many": {
+			language: "ru",
+			key:      "apples",
+			args:     locale.Args{"count": 11},
+			expected: "11 яблок",
+		},
+		"po context": {
+			language: "ru",
+			key:      "menu|quit",
+			expected: "Выход",
+		},
+		"fallback": {
+			language: "ru",
+			key:      "quit",
+			expected: "Quit",
+		},
+		"missing key": {
+			language: "ru",
+			key:      "missing",
+			expected: "missing",
+		},
+		"unknown placeholder": {
+			language: "en",
+			key:      "greeting",
+			args:     locale.Args{"other": 1},
+			expected: "Hello {name}",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loc := newTestLocale(t)
+			loc.SetLanguage(tc.language)
+
+			got := loc.Translate(tc.key, tc.args)
+			if got != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBinding(t *testing.T) {
+	loc := newTestLocale(t)
+
+	var text string
+
+	binding := loc.Bind("apples", locale.Args{"count": 2}, func(value string) {
+		text = value
+	})
+
+	if text != "2 apples" {
+		t.Fatalf("expected: 2 apples, got: %v", text)
+	}
+
+	loc.SetLanguage("ru")
+
+	if text != "2 яблока" {
+		t.Fatalf("expected: 2 яблока, got: %v", text)
+	}
+
+	binding.SetArgs(locale.Args{"count": 5})
+
+	if text != "5 яблок" {
+		t.Fatalf("expected: 5 яблок, got: %v", text)
+	}
+
+	binding.Unbind()
+	loc.SetLanguage("en")
+
+	if text != "5 яблок" {
+		t.Fatalf("expected unbound text to stay, got: %v", text)
+	}
+}
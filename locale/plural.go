@@ -0,0 +1,128 @@
+package locale
+
+import "strings"
+
+// PluralCategory is a CLDR plural category
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// PluralRule picks the category for a count and lists its categories in the
+// same order as gettext plural forms.
+type PluralRule struct {
+	Categories []PluralCategory
+	Select     func(n int) PluralCategory
+}
+
+var (
+	pluralOneOther = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			}
+
+			return PluralOther
+		},
+	}
+	pluralZeroOneOther = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 0 || n == 1 {
+				return PluralOne
+			}
+
+			return PluralOther
+		},
+	}
+	pluralOther = PluralRule{
+		Categories: []PluralCategory{PluralOther},
+		Select: func(n int) PluralCategory {
+			return PluralOther
+		},
+	}
+	pluralSlavic = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany},
+		Select: func(n int) PluralCategory {
+			mod10, mod100 := n%10, n%100
+
+			switch {
+			case mod10 == 1 && mod100 != 11:
+				return PluralOne
+			case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+				return PluralFew
+			default:
+				return PluralMany
+			}
+		},
+	}
+	pluralPolish = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany},
+		Select: func(n int) PluralCategory {
+			mod10, mod100 := n%10, n%100
+
+			switch {
+			case n == 1:
+				return PluralOne
+			case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+				return PluralFew
+			default:
+				return PluralMany
+			}
+		},
+	}
+)
+
+var pluralRules = map[string]PluralRule{
+	"de": pluralOneOther,
+	"en": pluralOneOther,
+	"es": pluralOneOther,
+	"it": pluralOneOther,
+	"nl": pluralOneOther,
+	"pt": pluralOneOther,
+	"sv": pluralOneOther,
+	"fr": pluralZeroOneOther,
+	"ja": pluralOther,
+	"ko": pluralOther,
+	"zh": pluralOther,
+	"ru": pluralSlavic,
+	"uk": pluralSlavic,
+	"pl": pluralPolish,
+}
+
+// RegisterPluralRule adds or replaces the plural rule of a language
+func RegisterPluralRule(language string, rule PluralRule) {
+	pluralRules[strings.ToLower(language)] = rule
+}
+
+// PluralRuleFor returns the rule of a language, trying the base language
+// if there is no exact match and defaulting to one and other.
+func PluralRuleFor(language string) PluralRule {
+	language = strings.ToLower(language)
+
+	if rule, ok := pluralRules[language]; ok {
+		return rule
+	}
+
+	if rule, ok := pluralRules[baseLanguage(language)]; ok {
+		return rule
+	}
+
+	return pluralOneOther
+}
+
+// baseLanguage returns "en" for "en-US" or "en_US"
+func baseLanguage(language string) string {
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		return language[:i]
+	}
+
+	return language
+}
@@ -0,0 +1,200 @@
+package locale
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Message is a single translated string, plural messages
+// have a form for each plural category.
+type Message struct {
+	Text    string
+	Plurals map[PluralCategory]string
+}
+
+// Table contains every message of a language
+type Table struct {
+	Language string
+	Messages map[string]Message
+}
+
+func NewTable(language string) *Table {
+	return &Table{
+		Language: language,
+		Messages: make(map[string]Message),
+	}
+}
+
+// ParseJSON reads a table where values are either strings or objects
+// of plural categories to strings.
+//
+//	{"greeting": "Hello {name}", "apples": {"one": "{count} apple", "other": "{count} apples"}}
+func ParseJSON(language string, data []byte) (*Table, error) {
+	var raw map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %v strings: %w", language, err)
+	}
+
+	table := NewTable(language)
+
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			table.Messages[key] = Message{Text: text}
+			continue
+		}
+
+		var plurals map[PluralCategory]string
+		if err := json.Unmarshal(value, &plurals); err != nil {
+			return nil, fmt.Errorf("parsing %v string %v: %w", language, key, err)
+		}
+
+		table.Messages[key] = Message{
+			Text:    plurals[PluralOther],
+			Plurals: plurals,
+		}
+	}
+
+	return table, nil
+}
+
+type poEntry struct {
+	context string
+	id      string
+	plural  string
+	str     string
+	strs    []string
+}
+
+func (e *poEntry) key() string {
+	if e.context != "" {
+		return e.context + "|" + e.id
+	}
+
+	return e.id
+}
+
+func (e *poEntry) message(rule PluralRule) Message {
+	if e.plural == "" {
+		return Message{Text: e.str}
+	}
+
+	msg := Message{
+		Plurals: make(map[PluralCategory]string, len(e.strs)),
+	}
+
+	for i, str := range e.strs {
+		if i < len(rule.Categories) {
+			msg.Plurals[rule.Categories[i]] = str
+		}
+	}
+
+	// the last form is the most general and a safe default
+	if len(e.strs) > 0 {
+		msg.Text = e.strs[len(e.strs)-1]
+		if _, ok := msg.Plurals[PluralOther]; !ok {
+			msg.Plurals[PluralOther] = msg.Text
+		}
+	}
+
+	return msg
+}
+
+// ParsePO reads a gettext PO file where msgid is the key.
+// Plural forms are mapped to categories in the order of the language plural rule,
+// messages with a context are keyed as "context|msgid".
+// Untranslated messages and the header are skipped.
+func ParsePO(language string, data []byte) (*Table, error) {
+	table := NewTable(language)
+	rule := PluralRuleFor(language)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	entry := &poEntry{}
+	lineNum := 0
+
+	var target *string
+
+	flush := func() {
+		msg := entry.message(rule)
+		if entry.id != "" && msg.Text != "" {
+			table.Messages[entry.key()] = msg
+		}
+
+		entry = &poEntry{}
+		target = nil
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, `"`) {
+			value, err := strconv.Unquote(line)
+			if err != nil || target == nil {
+				return nil, fmt.Errorf("parsing %v po line %v: invalid string", language, lineNum)
+			}
+
+			*target += value
+
+			continue
+		}
+
+		keyword, rest, _ := strings.Cut(line, " ")
+
+		value, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %v po line %v: %w", language, lineNum, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			flush()
+
+			entry.context = value
+			target = &entry.context
+		case keyword == "msgid":
+			if entry.id != "" || entry.str != "" || len(entry.strs) > 0 {
+				flush()
+			}
+
+			entry.id = value
+			target = &entry.id
+		case keyword == "msgid_plural":
+			entry.plural = value
+			target = &entry.plural
+		case keyword == "msgstr":
+			entry.str = value
+			target = &entry.str
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || index != len(entry.strs) {
+				return nil, fmt.Errorf("parsing %v po line %v: invalid plural index", language, lineNum)
+			}
+
+			entry.strs = append(entry.strs, value)
+			target = &entry.strs[index]
+		default:
+			return nil, fmt.Errorf(
+				"parsing %v po line %v: unknown keyword %v",
+				language, lineNum, keyword,
+			)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %v po: %w", language, err)
+	}
+
+	flush()
+
+	return table, nil
+}
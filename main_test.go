@@ -0,0 +1,38 @@
+//go:build !js
+
+package igloo_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// pixelGame runs our tests inside of the game loop so images can be read
+type pixelGame struct {
+	m    *testing.M
+	code int
+}
+
+func (g *pixelGame) Update() error {
+	canReadPixels = true
+	g.code = g.m.Run()
+
+	return ebiten.Termination
+}
+
+func (g *pixelGame) Draw(screen *ebiten.Image) {}
+
+func (g *pixelGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 320, 240
+}
+
+func TestMain(m *testing.M) {
+	g := &pixelGame{m: m, code: 1}
+	if err := ebiten.RunGame(g); err != nil {
+		panic(err)
+	}
+
+	os.Exit(g.code)
+}
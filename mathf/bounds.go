@@ -1,5 +1,11 @@
 package mathf
 
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
 type Bounds struct {
 	X      float64
 	Y      float64
@@ -55,3 +61,25 @@ func (b Bounds) Surrounds(o Bounds) bool {
 		b.Y < o.Y &&
 		b.Bottom() > o.Bottom())
 }
+
+// TransformBounds returns the bounding box of our corners after a matrix
+func TransformBounds(b Bounds, geom ebiten.GeoM) Bounds {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	corners := [4]Vec2{
+		{X: b.X, Y: b.Y},
+		{X: b.Right(), Y: b.Y},
+		{X: b.X, Y: b.Bottom()},
+		{X: b.Right(), Y: b.Bottom()},
+	}
+
+	for _, corner := range corners {
+		x, y := geom.Apply(corner.X, corner.Y)
+		minX = math.Min(minX, x)
+		minY = math.Min(minY, y)
+		maxX = math.Max(maxX, x)
+		maxY = math.Max(maxY, y)
+	}
+
+	return NewBoundsWidthHeight(minX, minY, maxX-minX, maxY-minY)
+}
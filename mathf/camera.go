@@ -0,0 +1,252 @@
+package mathf
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Camera is a viewport into a world space visual tree.
+// Attach the camera to the root transform of the world so every child
+// draws through the camera and pass View as the root when laying out
+// so only visuals inside of the camera are laid out.
+type Camera struct {
+	viewport Bounds
+	// position is where we are after limits, requested is the last
+	// position set so clearing our limits can return to it.
+	position    Vec2
+	requested   Vec2
+	zoom        float64
+	rotation    float64
+	target      *Transform
+	followSpeed float64
+	deadZone    Vec2
+	limits      Bounds
+	hasLimits   bool
+	isPaused    bool
+
+	geom ebiten.GeoM
+	view *Transform
+}
+
+// NewCamera creates a camera drawing into a viewport of width and height
+// at the top left of the screen, looking at the world origin.
+func NewCamera(width, height float64) *Camera {
+	c := &Camera{
+		viewport: NewBoundsWidthHeight(0, 0, width, height),
+		zoom:     1,
+		view:     NewTransform(),
+	}
+
+	c.build()
+
+	return c
+}
+
+// Attach draws a transform and its children through our camera
+func (c *Camera) Attach(root *Transform) {
+	root.SetView(&c.geom)
+}
+
+// View returns a transform covering the world space we can see,
+// pass it as the root to Layout for culling.
+func (c *Camera) View() *Transform {
+	return c.view
+}
+
+// GeoM returns our world to screen matrix
+func (c *Camera) GeoM() ebiten.GeoM {
+	return c.geom
+}
+
+func (c *Camera) Viewport() Bounds {
+	return c.viewport
+}
+
+// SetViewport sets the screen area we draw into
+func (c *Camera) SetViewport(viewport Bounds) {
+	c.viewport = viewport
+	c.build()
+}
+
+// Position returns the world point in the center of our viewport
+func (c *Camera) Position() Vec2 {
+	return c.position
+}
+
+func (c *Camera) SetPosition(position Vec2) {
+	c.requested = position
+	c.build()
+}
+
+func (c *Camera) Zoom() float64 {
+	return c.zoom
+}
+
+// SetZoom scales the world, above one zooms in, values at or below zero are ignored
+func (c *Camera) SetZoom(zoom float64) {
+	if zoom <= 0 {
+		return
+	}
+
+	c.zoom = zoom
+	c.build()
+}
+
+func (c *Camera) Rotation() float64 {
+	return c.rotation
+}
+
+// SetRotation rotates the world around our position in radians
+func (c *Camera) SetRotation(rotation float64) {
+	c.rotation = rotation
+	c.build()
+}
+
+// Follow moves towards the center of a target each tick, nil stops following
+func (c *Camera) Follow(target *Transform) {
+	c.target = target
+}
+
+// SetFollowSpeed sets how quickly we catch up to our target,
+// higher is faster and zero snaps to the target right away.
+func (c *Camera) SetFollowSpeed(speed float64) {
+	c.followSpeed = speed
+}
+
+// SetDeadZone sets the world size of an area around our position
+// the target can move inside of without the camera following.
+func (c *Camera) SetDeadZone(width, height float64) {
+	c.deadZone = Vec2{X: width, Y: height}
+}
+
+// SetLimits keeps our view inside of world bounds, when the bounds
+// are smaller than our view we center on the bounds. Rotation is ignored.
+func (c *Camera) SetLimits(limits Bounds) {
+	c.limits = limits
+	c.hasLimits = true
+	c.build()
+}
+
+// ClearLimits lets our view move anywhere, we move back to the last
+// position set if our limits had moved us.
+func (c *Camera) ClearLimits() {
+	c.hasLimits = false
+	c.build()
+}
+
+// ScreenToWorld converts a screen point such as the cursor to world space
+func (c *Camera) ScreenToWorld(screen Vec2) Vec2 {
+	geom := c.geom
+	if !geom.IsInvertible() {
+		return c.position
+	}
+
+	geom.Invert()
+	x, y := geom.Apply(screen.X, screen.Y)
+
+	return Vec2{X: x, Y: y}
+}
+
+// WorldToScreen converts a world point to screen space
+func (c *Camera) WorldToScreen(world Vec2) Vec2 {
+	x, y := c.geom.Apply(world.X, world.Y)
+	return Vec2{X: x, Y: y}
+}
+
+func (c *Camera) Resume() {
+	c.isPaused = false
+}
+
+func (c *Camera) Pause() {
+	c.isPaused = true
+}
+
+func (c *Camera) IsPaused() bool {
+	return c.isPaused
+}
+
+// Tick follows our target
+func (c *Camera) Tick() {
+	if c.target == nil || c.isPaused {
+		return
+	}
+
+	bounds := c.target.Bounds()
+	desired := c.position
+	center := Vec2{
+		X: bounds.X + bounds.Width/2,
+		Y: bounds.Y + bounds.Height/2,
+	}
+
+	desired.X = followAxis(desired.X, center.X, c.deadZone.X/2)
+	desired.Y = followAxis(desired.Y, center.Y, c.deadZone.Y/2)
+
+	if c.followSpeed > 0 {
+		// framerate independent smoothing
+		percent := 1 - math.Exp(-c.followSpeed*TickDelta())
+		desired.X = Lerp(c.position.X, desired.X, percent)
+		desired.Y = Lerp(c.position.Y, desired.Y, percent)
+	}
+
+	c.SetPosition(desired)
+}
+
+// followAxis moves position just enough to keep target within halfZone
+func followAxis(position, target, halfZone float64) float64 {
+	switch {
+	case target > position+halfZone:
+		return target - halfZone
+	case target < position-halfZone:
+		return target + halfZone
+	default:
+		return position
+	}
+}
+
+// clampAxis keeps a view of halfSize around position inside of start and end
+func clampAxis(position, halfSize, start, end float64) float64 {
+	if end-start <= halfSize*2 {
+		return (start + end) / 2
+	}
+
+	return Clamp(position, start+halfSize, end-halfSize)
+}
+
+// build updates our matrix and view after any change
+func (c *Camera) build() {
+	c.position = c.requested
+
+	if c.hasLimits {
+		c.position.X = clampAxis(
+			c.position.X, c.viewport.Width/c.zoom/2, c.limits.X, c.limits.Right(),
+		)
+		c.position.Y = clampAxis(
+			c.position.Y, c.viewport.Height/c.zoom/2, c.limits.Y, c.limits.Bottom(),
+		)
+	}
+
+	c.geom.Reset()
+	c.geom.Translate(-c.position.X, -c.position.Y)
+	c.geom.Rotate(-c.rotation)
+	c.geom.Scale(c.zoom, c.zoom)
+	c.geom.Translate(
+		c.viewport.X+c.viewport.Width/2,
+		c.viewport.Y+c.viewport.Height/2,
+	)
+
+	inverse := c.geom
+	if !inverse.IsInvertible() {
+		return
+	}
+
+	inverse.Invert()
+
+	visible := TransformBounds(c.viewport, inverse)
+
+	c.view.SetPosition(Vec2{X: visible.X, Y: visible.Y})
+	c.view.SetNaturalWidth(visible.Width)
+	c.view.SetNaturalHeight(visible.Height)
+	c.view.SetSize(visible.Width, visible.Height)
+	c.view.Build(nil)
+}
@@ -0,0 +1,130 @@
+package mathf_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func closeVec2(a, b mathf.Vec2) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestCameraWorldToScreen(t *testing.T) {
+	tests := map[string]struct {
+		position mathf.Vec2
+		zoom     float64
+		rotation float64
+		world    mathf.Vec2
+		screen   mathf.Vec2
+	}{
+		"origin at center": {
+			zoom:   1,
+			world:  mathf.Vec2{X: 0, Y: 0},
+			screen: mathf.Vec2{X: 50, Y: 40},
+		},
+		"moved": {
+			position: mathf.Vec2{X: 10, Y: 20},
+			zoom:     1,
+			world:    mathf.Vec2{X: 10, Y: 20},
+			screen:   mathf.Vec2{X: 50, Y: 40},
+		},
+		"zoomed": {
+			zoom:   2,
+			world:  mathf.Vec2{X: 5, Y: -5},
+			screen: mathf.Vec2{X: 60, Y: 30},
+		},
+		"rotated": {
+			zoom:     1,
+			rotation: math.Pi / 2,
+			world:    mathf.Vec2{X: 10, Y: 0},
+			screen:   mathf.Vec2{X: 50, Y: 30},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := mathf.NewCamera(100, 80)
+			c.SetPosition(tc.position)
+			c.SetZoom(tc.zoom)
+			c.SetRotation(tc.rotation)
+
+			screen := c.WorldToScreen(tc.world)
+			if !closeVec2(screen, tc.screen) {
+				t.Fatalf("expected: %v, got: %v", tc.screen, screen)
+			}
+
+			world := c.ScreenToWorld(screen)
+			if !closeVec2(world, tc.world) {
+				t.Fatalf("expected: %v, got: %v", tc.world, world)
+			}
+		})
+	}
+}
+
+func TestCameraView(t *testing.T) {
+	c := mathf.NewCamera(100, 80)
+	c.SetPosition(mathf.Vec2{X: 100, Y: 100})
+	c.SetZoom(2)
+
+	expected := mathf.NewBoundsWidthHeight(75, 80, 50, 40)
+	if got := c.View().Bounds(); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+
+	root := mathf.NewTransform()
+	child := mathf.NewTransform()
+
+	c.Attach(root)
+	root.Build(nil)
+	child.Build(root)
+
+	geom := child.GeoM()
+	x, y := geom.Apply(100, 100)
+	if x != 50 || y != 40 {
+		t.Fatalf("expected: 50, 40, got: %v, %v", x, y)
+	}
+}
+
+func TestCameraLimits(t *testing.T) {
+	c := mathf.NewCamera(100, 80)
+	c.SetLimits(mathf.NewBoundsWidthHeight(0, 0, 200, 60))
+	c.SetPosition(mathf.Vec2{X: -50, Y: 500})
+
+	// too short so we center vertically
+	expected := mathf.Vec2{X: 50, Y: 30}
+	if got := c.Position(); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+
+	// clearing our limits moves us back to where we were asked to be
+	c.ClearLimits()
+
+	expected = mathf.Vec2{X: -50, Y: 500}
+	if got := c.Position(); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+
+	expectedView := mathf.NewBoundsWidthHeight(-100, 460, 100, 80)
+	if got := c.View().Bounds(); got != expectedView {
+		t.Fatalf("expected: %v, got: %v", expectedView, got)
+	}
+}
+
+func TestCameraFollow(t *testing.T) {
+	target := mathf.NewTransform()
+	target.SetPosition(mathf.Vec2{X: 30, Y: 2})
+	target.Build(nil)
+
+	c := mathf.NewCamera(100, 80)
+	c.SetDeadZone(20, 10)
+	c.Follow(target)
+	c.Tick()
+
+	// only moved enough to bring the target to the edge of the dead zone
+	expected := mathf.Vec2{X: 20, Y: 0}
+	if got := c.Position(); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+}
@@ -253,3 +253,50 @@ func EaseInOutBounce(t float64) float64 {
 
 	return EaseOutBounce(2*t-1)*0.5 + 0.5
 }
+
+// easeNames maps names used in data files to ease functions
+var easeNames = map[string]EaseFunc{
+	"linear":       EaseLinear,
+	"inQuad":       EaseInQuad,
+	"outQuad":      EaseOutQuad,
+	"inOutQuad":    EaseInOutQuad,
+	"inCubic":      EaseInCubic,
+	"outCubic":     EaseOutCubic,
+	"inOutCubic":   EaseInOutCubic,
+	"inQuart":      EaseInQuart,
+	"outQuart":     EaseOutQuart,
+	"inOutQuart":   EaseInOutQuart,
+	"inQuint":      EaseInQuint,
+	"outQuint":     EaseOutQuint,
+	"inOutQuint":   EaseInOutQuint,
+	"inSine":       EaseInSine,
+	"outSine":      EaseOutSine,
+	"inOutSine":    EaseInOutSine,
+	"inExpo":       EaseInExpo,
+	"outExpo":      EaseOutExpo,
+	"inOutExpo":    EaseInOutExpo,
+	"inCirc":       EaseInCirc,
+	"outCirc":      EaseOutCirc,
+	"inOutCirc":    EaseInOutCirc,
+	"inElastic":    EaseInElastic,
+	"outElastic":   EaseOutElastic,
+	"inOutElastic": EaseInOutElastic,
+	"inBack":       EaseInBack,
+	"outBack":      EaseOutBack,
+	"inOutBack":    EaseInOutBack,
+	"inBounce":     EaseInBounce,
+	"outBounce":    EaseOutBounce,
+	"inOutBounce":  EaseInOutBounce,
+}
+
+// EaseNamed returns an ease function by name such as "inOutQuad",
+// an empty name is linear.
+func EaseNamed(name string) (EaseFunc, bool) {
+	if name == "" {
+		return EaseLinear, true
+	}
+
+	ease, ok := easeNames[name]
+
+	return ease, ok
+}
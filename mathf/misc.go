@@ -68,3 +68,15 @@ func Clamp(value, min, max float64) float64 {
 func Lerp(start, end, percent float64) float64 {
 	return start + (end-start)*percent
 }
+
+func ClampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+
+	if value > max {
+		return max
+	}
+
+	return value
+}
@@ -0,0 +1,144 @@
+package mathf
+
+import "image"
+
+type skylineSegment struct {
+	x     int
+	y     int
+	width int
+}
+
+// Skyline packs rectangles into a fixed size area using the bottom-left
+// skyline heuristic, it only tracks layout and knows nothing of images.
+type Skyline struct {
+	width    int
+	height   int
+	segments []skylineSegment
+}
+
+// NewSkyline creates an empty skyline of width and height
+func NewSkyline(width, height int) *Skyline {
+	return &Skyline{
+		width:  width,
+		height: height,
+		segments: []skylineSegment{
+			{x: 0, y: 0, width: width},
+		},
+	}
+}
+
+func (s *Skyline) Width() int {
+	return s.width
+}
+
+func (s *Skyline) Height() int {
+	return s.height
+}
+
+// Insert finds a place for a rectangle of width and height,
+// returning false if there is no room left.
+func (s *Skyline) Insert(width, height int) (image.Rectangle, bool) {
+	if width <= 0 || height <= 0 {
+		return image.Rectangle{}, false
+	}
+
+	bestIndex := -1
+	bestBottom := 0
+	bestWidth := 0
+	bestY := 0
+
+	for i := range s.segments {
+		y, ok := s.fit(i, width, height)
+		if !ok {
+			continue
+		}
+
+		bottom := y + height
+		if bestIndex == -1 || bottom < bestBottom ||
+			(bottom == bestBottom && s.segments[i].width < bestWidth) {
+			bestIndex = i
+			bestBottom = bottom
+			bestWidth = s.segments[i].width
+			bestY = y
+		}
+	}
+
+	if bestIndex == -1 {
+		return image.Rectangle{}, false
+	}
+
+	x := s.segments[bestIndex].x
+	s.place(bestIndex, x, bestY+height, width)
+
+	return image.Rect(x, bestY, x+width, bestY+height), true
+}
+
+// fit returns the y position a rectangle would rest at if placed
+// starting at the segment index.
+func (s *Skyline) fit(index, width, height int) (int, bool) {
+	x := s.segments[index].x
+	if x+width > s.width {
+		return 0, false
+	}
+
+	y := 0
+	widthLeft := width
+
+	for i := index; widthLeft > 0; i++ {
+		if i >= len(s.segments) {
+			return 0, false
+		}
+
+		if s.segments[i].y > y {
+			y = s.segments[i].y
+		}
+
+		if y+height > s.height {
+			return 0, false
+		}
+
+		widthLeft -= s.segments[i].width
+	}
+
+	return y, true
+}
+
+func (s *Skyline) place(index, x, y, width int) {
+	segment := skylineSegment{x: x, y: y, width: width}
+
+	s.segments = append(s.segments, skylineSegment{})
+	copy(s.segments[index+1:], s.segments[index:])
+	s.segments[index] = segment
+
+	// shrink or remove any segments now covered by the new one
+	for i := index + 1; i < len(s.segments); {
+		prev := s.segments[i-1]
+		prevRight := prev.x + prev.width
+
+		if s.segments[i].x >= prevRight {
+			break
+		}
+
+		shrink := prevRight - s.segments[i].x
+		s.segments[i].x += shrink
+		s.segments[i].width -= shrink
+
+		if s.segments[i].width > 0 {
+			break
+		}
+
+		s.segments = append(s.segments[:i], s.segments[i+1:]...)
+	}
+
+	// merge neighbors at the same height
+	for i := 0; i < len(s.segments)-1; {
+		if s.segments[i].y == s.segments[i+1].y {
+			s.segments[i].width += s.segments[i+1].width
+			s.segments = append(s.segments[:i+1], s.segments[i+2:]...)
+
+			continue
+		}
+
+		i++
+	}
+}
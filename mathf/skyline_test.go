@@ -0,0 +1,99 @@
+package mathf_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestSkylineInsert(t *testing.T) {
+	tests := map[string]struct {
+		sizes    []image.Point
+		expected []image.Rectangle
+		fits     []bool
+	}{
+		"single": {
+			sizes:    []image.Point{{X: 10, Y: 10}},
+			expected: []image.Rectangle{image.Rect(0, 0, 10, 10)},
+			fits:     []bool{true},
+		},
+		"fills row then stacks": {
+			sizes: []image.Point{{X: 20, Y: 10}, {X: 12, Y: 5}, {X: 20, Y: 10}},
+			expected: []image.Rectangle{
+				image.Rect(0, 0, 20, 10),
+				image.Rect(20, 0, 32, 5),
+				image.Rect(0, 10, 20, 20),
+			},
+			fits: []bool{true, true, true},
+		},
+		"prefers lowest resting point": {
+			sizes: []image.Point{{X: 16, Y: 20}, {X: 16, Y: 4}, {X: 16, Y: 4}},
+			expected: []image.Rectangle{
+				image.Rect(0, 0, 16, 20),
+				image.Rect(16, 0, 32, 4),
+				image.Rect(16, 4, 32, 8),
+			},
+			fits: []bool{true, true, true},
+		},
+		"too wide": {
+			sizes:    []image.Point{{X: 33, Y: 1}},
+			expected: []image.Rectangle{{}},
+			fits:     []bool{false},
+		},
+		"full": {
+			sizes: []image.Point{{X: 32, Y: 32}, {X: 1, Y: 1}},
+			expected: []image.Rectangle{
+				image.Rect(0, 0, 32, 32),
+				{},
+			},
+			fits: []bool{true, false},
+		},
+		"empty": {
+			sizes:    []image.Point{{X: 0, Y: 4}},
+			expected: []image.Rectangle{{}},
+			fits:     []bool{false},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			skyline := mathf.NewSkyline(32, 32)
+
+			for i, size := range tc.sizes {
+				got, ok := skyline.Insert(size.X, size.Y)
+				if ok != tc.fits[i] {
+					t.Fatalf("insert %v expected fit: %v, got: %v", i, tc.fits[i], ok)
+				}
+
+				if got != tc.expected[i] {
+					t.Fatalf("insert %v expected: %v, got: %v", i, tc.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestSkylineNoOverlaps(t *testing.T) {
+	skyline := mathf.NewSkyline(128, 128)
+	placed := make([]image.Rectangle, 0)
+
+	for i := 0; i < 200; i++ {
+		rect, ok := skyline.Insert(3+i%11, 2+(i*7)%13)
+		if !ok {
+			continue
+		}
+
+		if !rect.In(image.Rect(0, 0, 128, 128)) {
+			t.Fatalf("rect %v outside of skyline", rect)
+		}
+
+		for _, other := range placed {
+			if rect.Overlaps(other) {
+				t.Fatalf("rect %v overlaps %v", rect, other)
+			}
+		}
+
+		placed = append(placed, rect)
+	}
+}
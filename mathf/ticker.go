@@ -1,5 +1,7 @@
 package mathf
 
+import "github.com/hajimehoshi/ebiten/v2"
+
 type TickerImp interface {
 	Resume()        // resumes ticking
 	Pause()         // stops ticking but keeps state
@@ -26,3 +28,8 @@ func (t *Ticker) Tick() {
 		}
 	}
 }
+
+// TickDelta returns the time in seconds covered by a single tick.
+func TickDelta() float64 {
+	return 1 / float64(ebiten.TPS())
+}
@@ -17,8 +17,11 @@ type Transform struct {
 	naturalWidth  float64 // native width of our source graphic
 	naturalHeight float64 // native height of our source graphic
 
-	bounds  Bounds      // calculated bounding box
-	geom    ebiten.GeoM // calculated geom matrix
+	bounds  Bounds       // calculated bounding box
+	geom    ebiten.GeoM  // calculated geom matrix
+	view    *ebiten.GeoM // world to screen matrix shared with our children
+	ownView *ebiten.GeoM // view set on us instead of our parents view
+	content *ebiten.GeoM // view for our children instead of our view
 	isDirty bool
 }
 
@@ -32,10 +35,67 @@ func (t *Transform) Clean() {
 	t.isDirty = false
 }
 
+// GeoM returns our matrix from local to screen space including our view
 func (t *Transform) GeoM() ebiten.GeoM {
+	if t.view == nil {
+		return t.geom
+	}
+
+	geom := t.geom
+	geom.Concat(*t.view)
+
+	return geom
+}
+
+// WorldGeoM returns our matrix from local to world space without our view
+func (t *Transform) WorldGeoM() ebiten.GeoM {
 	return t.geom
 }
 
+// View returns the world to screen matrix, identity without a view
+func (t *Transform) View() ebiten.GeoM {
+	if t.view == nil {
+		return ebiten.GeoM{}
+	}
+
+	return *t.view
+}
+
+// SetView sets the world to screen matrix for us and our children,
+// usually from a Camera, and marks us as dirty so our children are rebuilt
+// with it. The matrix is read each time GeoM is called so changes to it
+// apply without rebuilding.
+func (t *Transform) SetView(view *ebiten.GeoM) {
+	if t.ownView == view {
+		return
+	}
+
+	t.ownView = view
+	t.view = view
+	t.isDirty = true
+}
+
+// SetContentView sets the view for our children instead of our own view,
+// for containers drawing their children somewhere other than the screen.
+func (t *Transform) SetContentView(view *ebiten.GeoM) {
+	if t.content == view {
+		return
+	}
+
+	t.content = view
+	t.isDirty = true
+}
+
+// ScreenBounds returns our bounds in screen space after our view,
+// when rotated this is the bounding box of our rotated bounds.
+func (t *Transform) ScreenBounds() Bounds {
+	if t.view == nil {
+		return t.bounds
+	}
+
+	return TransformBounds(t.bounds, *t.view)
+}
+
 // X will return the x value
 func (t *Transform) X() float64 {
 	return t.position.X
@@ -368,6 +428,14 @@ func (t *Transform) InView(other *Transform) bool {
 func (t *Transform) Build(parent *Transform) {
 	t.geom.Reset()
 
+	t.view = t.ownView
+	if t.view == nil && parent != nil {
+		t.view = parent.view
+		if parent.content != nil {
+			t.view = parent.content
+		}
+	}
+
 	// check if we are stretched horizontally
 	// if so determine our width from our parent bounds
 	// if not use our defined width
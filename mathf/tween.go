@@ -77,6 +77,11 @@ func (t *Tween) IsPaused() bool {
 }
 
 func (t *Tween) Tick() {
+	if t.firstStart {
+		t.firstStart = false
+		t.started(t)
+	}
+
 	if t.percent >= 1 {
 		t.percent = 1
 
@@ -104,6 +109,17 @@ func (t *Tween) Tick() {
 	}
 
 	t.valueFunc(easedPercent)
+
+	if t.isPaused {
+		return
+	}
+
+	if t.duration <= 0 {
+		t.percent = 1
+		return
+	}
+
+	t.percent += TickDelta() / t.duration
 }
 
 func TweenVec2Func(start, end Vec2, fn func(Vec2)) TweenValueFunc {
@@ -0,0 +1,110 @@
+package mathf_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestTweenTick(t *testing.T) {
+	// half a second of ticks and then some so floating point error
+	// can not leave us one tick short
+	ticks := int(0.5/mathf.TickDelta()) + 2
+
+	tests := map[string]struct {
+		repeat         mathf.TweenRepeatMode
+		ticks          int
+		expectedValue  float64
+		expectedPaused bool
+		expectedDone   int
+	}{
+		"no repeat completes and pauses": {
+			repeat:         mathf.TweenNoRepeat,
+			ticks:          ticks,
+			expectedValue:  1,
+			expectedPaused: true,
+			expectedDone:   1,
+		},
+		"halfway": {
+			repeat:         mathf.TweenNoRepeat,
+			ticks:          ticks / 2,
+			expectedValue:  0.5,
+			expectedPaused: false,
+			expectedDone:   0,
+		},
+		"loop starts over": {
+			repeat:         mathf.TweenRepeatLoop,
+			ticks:          ticks,
+			expectedValue:  0,
+			expectedPaused: false,
+			expectedDone:   1,
+		},
+		"bounce returns and pauses": {
+			repeat:         mathf.TweenRepeatBounce,
+			ticks:          ticks,
+			expectedValue:  1,
+			expectedPaused: true,
+			expectedDone:   1,
+		},
+		"bounce loop keeps going": {
+			repeat:         mathf.TweenRepeatBounceLoop,
+			ticks:          ticks * 2,
+			expectedValue:  0,
+			expectedPaused: false,
+			expectedDone:   2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			value := -1.0
+			started := 0
+			done := 0
+
+			tween := mathf.NewTween(
+				0.5,
+				mathf.TweenUpdatePointer(&value),
+				mathf.TweenWithRepeat(tc.repeat),
+				mathf.TweenOnStart(func(*mathf.Tween) { started++ }),
+				mathf.TweenOnComplete(func(*mathf.Tween) { done++ }),
+				mathf.TweenPlay(),
+			)
+
+			ticker := mathf.NewTicker()
+			ticker.Add(tween)
+
+			for i := 0; i < tc.ticks; i++ {
+				ticker.Tick()
+			}
+
+			if started != 1 {
+				t.Fatalf("expected: 1 start, got: %v", started)
+			}
+
+			if done != tc.expectedDone {
+				t.Fatalf("expected: %v completions, got: %v", tc.expectedDone, done)
+			}
+
+			if tween.IsPaused() != tc.expectedPaused {
+				t.Fatalf("expected: paused %v, got: %v", tc.expectedPaused, tween.IsPaused())
+			}
+
+			if math.Abs(value-tc.expectedValue) > 0.1 {
+				t.Fatalf("expected: %v, got: %v", tc.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestTweenZeroDuration(t *testing.T) {
+	value := 0.0
+	tween := mathf.NewTween(0, mathf.TweenUpdatePointer(&value), mathf.TweenPlay())
+
+	tween.Tick()
+	tween.Tick()
+
+	if value != 1 || !tween.IsPaused() {
+		t.Fatalf("expected: 1 and paused, got: %v and %v", value, tween.IsPaused())
+	}
+}
@@ -0,0 +1,171 @@
+package igloo
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+type atlasPage struct {
+	image   *ebiten.Image
+	skyline *mathf.Skyline
+}
+
+// AtlasPacker packs many small images into a few large pages at load time
+// so sprites share a source image and can be batched when drawn.
+type AtlasPacker struct {
+	pageSize int
+	padding  int
+	extrude  int
+	pages    []*atlasPage
+}
+
+type AtlasPackerOption func(p *AtlasPacker)
+
+// AtlasPackerWithPageSize sets the width and height of each page, default 2048
+func AtlasPackerWithPageSize(size int) AtlasPackerOption {
+	return func(p *AtlasPacker) {
+		p.pageSize = size
+	}
+}
+
+// AtlasPackerWithPadding sets the empty pixels between images, default 2
+func AtlasPackerWithPadding(padding int) AtlasPackerOption {
+	return func(p *AtlasPacker) {
+		p.padding = padding
+	}
+}
+
+// AtlasPackerWithExtrude sets how many pixels the edges of each image are
+// repeated outwards to avoid bleeding when filtering, default 1
+func AtlasPackerWithExtrude(extrude int) AtlasPackerOption {
+	return func(p *AtlasPacker) {
+		p.extrude = extrude
+	}
+}
+
+func NewAtlasPacker(options ...AtlasPackerOption) *AtlasPacker {
+	p := &AtlasPacker{
+		pageSize: 2048,
+		padding:  2,
+		extrude:  1,
+	}
+
+	for _, o := range options {
+		o(p)
+	}
+
+	return p
+}
+
+// Pages returns every page image created so far
+func (p *AtlasPacker) Pages() []*ebiten.Image {
+	pages := make([]*ebiten.Image, len(p.pages))
+	for i, page := range p.pages {
+		pages[i] = page.image
+	}
+
+	return pages
+}
+
+// Pack adds img to a page and returns a sprite of the packed region.
+// Images too large for a page are given their own image instead,
+// empty images can not be packed.
+func (p *AtlasPacker) Pack(name string, img image.Image) (*content.Sprite, error) {
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, fmt.Errorf("packing %v: image is empty", name)
+	}
+
+	border := p.extrude*2 + p.padding
+	packWidth, packHeight := size.X+border, size.Y+border
+
+	if packWidth > p.pageSize || packHeight > p.pageSize {
+		return &content.Sprite{
+			Image: ebiten.NewImageFromImage(img),
+			Name:  name,
+		}, nil
+	}
+
+	page, rect, ok := p.insert(packWidth, packHeight)
+	if !ok {
+		return nil, fmt.Errorf("packing %v: no room in an empty page", name)
+	}
+
+	// our region excludes the padding which is only on the right and bottom
+	region := image.Rect(
+		rect.Min.X,
+		rect.Min.Y,
+		rect.Max.X-p.padding,
+		rect.Max.Y-p.padding,
+	)
+
+	extruded := extrudeImage(img, p.extrude)
+	page.image.SubImage(region).(*ebiten.Image).WritePixels(extruded.Pix)
+
+	inner := region.Inset(p.extrude)
+
+	return &content.Sprite{
+		Image: page.image.SubImage(inner).(*ebiten.Image),
+		Page:  page.image,
+		Name:  name,
+	}, nil
+}
+
+func (p *AtlasPacker) insert(width, height int) (*atlasPage, image.Rectangle, bool) {
+	for _, page := range p.pages {
+		if rect, ok := page.skyline.Insert(width, height); ok {
+			return page, rect, true
+		}
+	}
+
+	skyline := mathf.NewSkyline(p.pageSize, p.pageSize)
+
+	// only create a page once we know we fit in it
+	rect, ok := skyline.Insert(width, height)
+	if !ok {
+		return nil, image.Rectangle{}, false
+	}
+
+	page := &atlasPage{
+		image:   ebiten.NewImage(p.pageSize, p.pageSize),
+		skyline: skyline,
+	}
+	p.pages = append(p.pages, page)
+
+	return page, rect, true
+}
+
+// extrudeImage copies img into a premultiplied image with edge pixels
+// repeated outward by amount.
+func extrudeImage(img image.Image, amount int) *image.RGBA {
+	bounds := img.Bounds()
+	size := bounds.Size()
+	out := image.NewRGBA(image.Rect(0, 0, size.X+amount*2, size.Y+amount*2))
+
+	draw.Draw(out, image.Rect(amount, amount, amount+size.X, amount+size.Y), img, bounds.Min, draw.Src)
+
+	if amount == 0 || size.X == 0 || size.Y == 0 {
+		return out
+	}
+
+	for y := 0; y < out.Rect.Dy(); y++ {
+		srcY := mathf.ClampInt(y, amount, amount+size.Y-1)
+
+		for x := 0; x < out.Rect.Dx(); x++ {
+			srcX := mathf.ClampInt(x, amount, amount+size.X-1)
+			if srcX == x && srcY == y {
+				continue
+			}
+
+			out.SetRGBA(x, y, out.RGBAAt(srcX, srcY))
+		}
+	}
+
+	return out
+}
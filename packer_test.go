@@ -0,0 +1,67 @@
+package igloo_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/miniscruff/igloo"
+)
+
+func TestAtlasPackerPack(t *testing.T) {
+	tests := map[string]struct {
+		sizes     []image.Point
+		pages     int
+		expectErr bool
+	}{
+		"share a page": {
+			sizes: []image.Point{{X: 4, Y: 4}, {X: 8, Y: 2}},
+			pages: 1,
+		},
+		"new page when full": {
+			sizes: []image.Point{{X: 10, Y: 10}, {X: 10, Y: 10}},
+			pages: 2,
+		},
+		"too large gets its own image": {
+			sizes: []image.Point{{X: 40, Y: 4}},
+			pages: 0,
+		},
+		"empty": {
+			sizes:     []image.Point{{X: 0, Y: 4}},
+			pages:     0,
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			packer := igloo.NewAtlasPacker(
+				igloo.AtlasPackerWithPageSize(16),
+				igloo.AtlasPackerWithPadding(1),
+				igloo.AtlasPackerWithExtrude(1),
+			)
+
+			for _, size := range tc.sizes {
+				sprite, err := packer.Pack(name, image.NewRGBA(image.Rect(0, 0, size.X, size.Y)))
+				if tc.expectErr {
+					if err == nil {
+						t.Fatal("expected error")
+					}
+
+					continue
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := sprite.Image.Bounds().Size(); got != size {
+					t.Fatalf("expected: %v, got: %v", size, got)
+				}
+			}
+
+			if len(packer.Pages()) != tc.pages {
+				t.Fatalf("expected: %v pages, got: %v", tc.pages, len(packer.Pages()))
+			}
+		})
+	}
+}
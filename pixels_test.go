@@ -0,0 +1,23 @@
+package igloo_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// canReadPixels is set when our tests run inside of the game loop,
+// images can not be read before the game starts.
+var canReadPixels bool
+
+// pixelAt reads a pixel or skips the test when pixels can not be read
+func pixelAt(t *testing.T, img *ebiten.Image, x, y int) color.RGBA {
+	t.Helper()
+
+	if !canReadPixels {
+		t.Skip("reading pixels needs the game loop")
+	}
+
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
@@ -0,0 +1,158 @@
+package igloo
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// postEntry is a named pass in a chain
+type postEntry struct {
+	name    string
+	pass    PostPass
+	enabled bool
+}
+
+// PostChain runs an ordered list of post processing passes over a full
+// screen image. Each pass reads the output of the previous pass and the
+// last pass draws into the destination.
+type PostChain struct {
+	entries []*postEntry
+	buffers [2]*ebiten.Image
+}
+
+func NewPostChain() *PostChain {
+	return &PostChain{
+		entries: make([]*postEntry, 0),
+	}
+}
+
+// Add appends an enabled pass to the end of our chain,
+// adding a name we already have replaces that pass in place.
+func (c *PostChain) Add(name string, pass PostPass) {
+	if entry := c.entry(name); entry != nil {
+		entry.pass = pass
+		return
+	}
+
+	c.entries = append(c.entries, &postEntry{
+		name:    name,
+		pass:    pass,
+		enabled: true,
+	})
+}
+
+// Remove a pass by name
+func (c *PostChain) Remove(name string) {
+	for i, entry := range c.entries {
+		if entry.name == name {
+			c.entries = append(c.entries[:i], c.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Pass returns a pass by name or nil if we do not have one
+func (c *PostChain) Pass(name string) PostPass {
+	if entry := c.entry(name); entry != nil {
+		return entry.pass
+	}
+
+	return nil
+}
+
+// Names returns the names of our passes in order
+func (c *PostChain) Names() []string {
+	names := make([]string, len(c.entries))
+	for i, entry := range c.entries {
+		names[i] = entry.name
+	}
+
+	return names
+}
+
+// SetEnabled toggles a pass by name, disabled passes are skipped
+func (c *PostChain) SetEnabled(name string, enabled bool) {
+	if entry := c.entry(name); entry != nil {
+		entry.enabled = enabled
+	}
+}
+
+func (c *PostChain) IsEnabled(name string) bool {
+	entry := c.entry(name)
+	return entry != nil && entry.enabled
+}
+
+// IsActive returns whether or not any of our passes are enabled
+func (c *PostChain) IsActive() bool {
+	for _, entry := range c.entries {
+		if entry.enabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Draw runs our enabled passes over src and draws the result over dest,
+// src is drawn as is when no passes are enabled.
+func (c *PostChain) Draw(dest, src *ebiten.Image) {
+	last := -1
+
+	for i, entry := range c.entries {
+		if entry.enabled {
+			last = i
+		}
+	}
+
+	if last < 0 {
+		dest.DrawImage(src, nil)
+		return
+	}
+
+	current := src
+	size := src.Bounds().Size()
+	buffer := 0
+
+	for i, entry := range c.entries[:last+1] {
+		if !entry.enabled {
+			continue
+		}
+
+		if i == last {
+			entry.pass.Apply(dest, current)
+			break
+		}
+
+		// swap between our buffers so we never read and write the same image
+		c.buffers[buffer] = FitImage(c.buffers[buffer], size)
+		c.buffers[buffer].Clear()
+		entry.pass.Apply(c.buffers[buffer], current)
+		current = c.buffers[buffer]
+		buffer = 1 - buffer
+	}
+}
+
+func (c *PostChain) entry(name string) *postEntry {
+	for _, entry := range c.entries {
+		if entry.name == name {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// FitImage returns img if it is size or a new image of size, disposing of
+// img when it is replaced. Use it to reuse offscreen images between frames.
+func FitImage(img *ebiten.Image, size image.Point) *ebiten.Image {
+	if img != nil && img.Bounds().Size() == size {
+		return img
+	}
+
+	if img != nil {
+		img.Dispose()
+	}
+
+	return ebiten.NewImage(size.X, size.Y)
+}
@@ -0,0 +1,81 @@
+package igloo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+)
+
+// namedPass records its name when applied
+type namedPass struct {
+	name    string
+	applied *[]string
+}
+
+func (p *namedPass) Apply(dest, src *ebiten.Image) {
+	*p.applied = append(*p.applied, p.name)
+
+	if dest == src {
+		*p.applied = append(*p.applied, "same image")
+	}
+}
+
+func TestPostChain(t *testing.T) {
+	tests := map[string]struct {
+		disabled []string
+		removed  []string
+		expected []string
+	}{
+		"all passes in order": {
+			expected: []string{"bloom", "vignette", "crt"},
+		},
+		"disabled passes are skipped": {
+			disabled: []string{"vignette"},
+			expected: []string{"bloom", "crt"},
+		},
+		"disabled last pass": {
+			disabled: []string{"crt"},
+			expected: []string{"bloom", "vignette"},
+		},
+		"removed passes are skipped": {
+			removed:  []string{"bloom"},
+			expected: []string{"vignette", "crt"},
+		},
+		"nothing enabled": {
+			disabled: []string{"bloom", "vignette", "crt"},
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			applied := []string{}
+			chain := igloo.NewPostChain()
+
+			for _, pass := range []string{"bloom", "vignette", "crt"} {
+				chain.Add(pass, &namedPass{name: pass, applied: &applied})
+			}
+
+			for _, pass := range tc.disabled {
+				chain.SetEnabled(pass, false)
+			}
+
+			for _, pass := range tc.removed {
+				chain.Remove(pass)
+			}
+
+			chain.Draw(ebiten.NewImage(8, 8), ebiten.NewImage(8, 8))
+
+			if !reflect.DeepEqual(applied, tc.expected) {
+				t.Fatalf("expected: %v, got: %v", tc.expected, applied)
+			}
+
+			if chain.IsActive() != (len(tc.expected) > 0) {
+				t.Fatalf("expected: active %v, got: %v", len(tc.expected) > 0, chain.IsActive())
+			}
+		})
+	}
+}
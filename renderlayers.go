@@ -0,0 +1,147 @@
+package igloo
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Default render layer names, drawn in this order
+const (
+	LayerBackground = "background"
+	LayerWorld      = "world"
+	LayerUI         = "ui"
+	LayerOverlay    = "overlay"
+)
+
+// RenderLayer collects visuals from anywhere in a tree to draw together
+type RenderLayer struct {
+	Name string
+	// YSort draws visuals with a lower bottom edge on top,
+	// visuals with the same bottom keep their tree order.
+	YSort bool
+
+	visuals []*Visualer
+}
+
+// RenderLayers draws visual trees one layer at a time so visuals deep in
+// a tree can draw above or below the rest. Each visual is drawn in the
+// layer set by SetLayer, or its parents layer, or the default layer.
+// Within a layer visuals are drawn in the same order as Visualer.Draw.
+type RenderLayers struct {
+	layers       []*RenderLayer
+	defaultLayer *RenderLayer
+}
+
+// NewRenderLayers creates layers drawn in the order given,
+// the first layer is the default layer.
+func NewRenderLayers(names ...string) *RenderLayers {
+	r := &RenderLayers{
+		layers: make([]*RenderLayer, len(names)),
+	}
+
+	for i, name := range names {
+		r.layers[i] = &RenderLayer{Name: name}
+	}
+
+	if len(r.layers) > 0 {
+		r.defaultLayer = r.layers[0]
+	}
+
+	return r
+}
+
+// DefaultRenderLayers creates background, world, ui and overlay layers
+// with world as the default layer.
+func DefaultRenderLayers() *RenderLayers {
+	r := NewRenderLayers(LayerBackground, LayerWorld, LayerUI, LayerOverlay)
+	r.SetDefaultLayer(LayerWorld)
+
+	return r
+}
+
+// Layer returns a layer by name or nil if we do not have one
+func (r *RenderLayers) Layer(name string) *RenderLayer {
+	for _, layer := range r.layers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+
+	return nil
+}
+
+// SetDefaultLayer sets the layer used by visuals without a layer,
+// or with a layer we do not have.
+func (r *RenderLayers) SetDefaultLayer(name string) {
+	if layer := r.Layer(name); layer != nil {
+		r.defaultLayer = layer
+	}
+}
+
+// Draw collects every visible visual in our roots into layers
+// and then draws each layer in order.
+func (r *RenderLayers) Draw(dest *ebiten.Image, roots ...*Visualer) {
+	if r.defaultLayer == nil {
+		return
+	}
+
+	for _, root := range roots {
+		r.collect(root, r.defaultLayer)
+	}
+
+	for _, layer := range r.layers {
+		if layer.YSort {
+			sort.SliceStable(layer.visuals, func(i, j int) bool {
+				return layer.visuals[i].Bounds().Bottom() < layer.visuals[j].Bounds().Bottom()
+			})
+		}
+
+		for _, v := range layer.visuals {
+			if childDrawer, ok := v.Drawer.(ChildDrawer); ok {
+				childDrawer.DrawWithChildren(dest)
+			} else {
+				v.Drawer.Draw(dest)
+			}
+		}
+
+		// clear our references so removed visuals can be collected
+		for i := range layer.visuals {
+			layer.visuals[i] = nil
+		}
+
+		layer.visuals = layer.visuals[:0]
+	}
+}
+
+func (r *RenderLayers) collect(v *Visualer, parentLayer *RenderLayer) {
+	if !v.visible {
+		return
+	}
+
+	layer := parentLayer
+	if v.layer != "" {
+		layer = r.Layer(v.layer)
+		if layer == nil {
+			layer = r.defaultLayer
+		}
+	}
+
+	// visuals drawing their own children are drawn as one
+	if _, ok := v.Drawer.(ChildDrawer); ok {
+		layer.visuals = append(layer.visuals, v)
+		return
+	}
+
+	if v.drawParentFirst {
+		layer.visuals = append(layer.visuals, v)
+	}
+
+	for _, child := range v.DrawOrder() {
+		r.collect(child, layer)
+	}
+
+	if !v.drawParentFirst {
+		layer.visuals = append(layer.visuals, v)
+	}
+}
@@ -0,0 +1,143 @@
+package igloo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+// namedVisual records its name when drawn
+type namedVisual struct {
+	*igloo.Visualer
+	name  string
+	drawn *[]string
+}
+
+func (v *namedVisual) IsDirty() bool {
+	return false
+}
+
+func (v *namedVisual) Clean() {}
+
+func (v *namedVisual) NativeSize() (float64, float64) {
+	return 0, 0
+}
+
+func (v *namedVisual) Draw(dest *ebiten.Image) {
+	*v.drawn = append(*v.drawn, v.name)
+}
+
+func newNamedVisual(name string, y float64, drawn *[]string) *igloo.Visualer {
+	v := &namedVisual{name: name, drawn: drawn}
+	v.Visualer = &igloo.Visualer{
+		Transform:   mathf.NewTransform(),
+		Children:    make([]*igloo.Visualer, 0),
+		Dirtier:     v,
+		Drawer:      v,
+		NativeSizer: v,
+	}
+
+	v.Transform.SetY(y)
+	v.Transform.Build(nil)
+	v.SetVisible(true)
+
+	return v.Visualer
+}
+
+func TestVisualerDrawOrder(t *testing.T) {
+	var drawn []string
+
+	root := newNamedVisual("root", 0, &drawn)
+	a := newNamedVisual("a", 0, &drawn)
+	b := newNamedVisual("b", 0, &drawn)
+	c := newNamedVisual("c", 0, &drawn)
+
+	root.InsertChild(a)
+	root.InsertChild(b)
+	root.InsertChild(c)
+	a.SetZIndex(1)
+
+	root.Draw(nil)
+
+	expected := []string{"b", "c", "a", "root"}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, drawn)
+	}
+
+	drawn = drawn[:0]
+	root.SetDrawParentFirst(true)
+	root.Draw(nil)
+
+	expected = []string{"root", "b", "c", "a"}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, drawn)
+	}
+}
+
+func TestRenderLayers(t *testing.T) {
+	var drawn []string
+
+	root := newNamedVisual("root", 0, &drawn)
+	sky := newNamedVisual("sky", 0, &drawn)
+	front := newNamedVisual("front", 20, &drawn)
+	back := newNamedVisual("back", 10, &drawn)
+	hud := newNamedVisual("hud", 0, &drawn)
+	health := newNamedVisual("health", 0, &drawn)
+
+	root.InsertChild(hud)
+	root.InsertChild(front)
+	root.InsertChild(back)
+	root.InsertChild(sky)
+	hud.InsertChild(health)
+
+	sky.SetLayer(igloo.LayerBackground)
+	hud.SetLayer(igloo.LayerUI)
+
+	layers := igloo.DefaultRenderLayers()
+	layers.Layer(igloo.LayerWorld).YSort = true
+	layers.Draw(nil, root)
+
+	// the world layer is sorted by the bottom of each visual
+	expected := []string{"sky", "root", "back", "front", "health", "hud"}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, drawn)
+	}
+}
+
+func TestVisualerRemoveChild(t *testing.T) {
+	var drawn []string
+
+	root := newNamedVisual("root", 0, &drawn)
+	a := newNamedVisual("a", 0, &drawn)
+	b := newNamedVisual("b", 0, &drawn)
+	c := newNamedVisual("c", 0, &drawn)
+	other := newNamedVisual("other", 0, &drawn)
+
+	root.InsertChild(a)
+	root.InsertChild(b)
+	root.InsertChild(c)
+	root.Draw(nil)
+
+	// swapping a child keeps the same count of children
+	root.RemoveChild(b)
+	root.RemoveChild(other)
+	root.InsertChild(other)
+	root.ClearSubtreeChanged()
+	root.RemoveChild(a)
+
+	drawn = drawn[:0]
+	root.Draw(nil)
+
+	expected := []string{"c", "other", "root"}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, drawn)
+	}
+
+	if a.Parent != nil || b.Parent != nil || !root.SubtreeChanged() {
+		t.Fatalf("expected: removed children without a parent and a changed subtree")
+	}
+}
@@ -0,0 +1,453 @@
+package igloo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/miniscruff/igloo/content"
+)
+
+// Tiled stores flip flags in the highest bits of each global tile ID
+const (
+	tiledFlipH     uint32 = 0x80000000
+	tiledFlipV     uint32 = 0x40000000
+	tiledFlipD     uint32 = 0x20000000
+	tiledRotateHex uint32 = 0x10000000
+	tiledGIDMask          = ^(tiledFlipH | tiledFlipV | tiledFlipD | tiledRotateHex)
+)
+
+// tiledTilesetDef is a tileset definition shared by the TMX and JSON formats
+type tiledTilesetDef struct {
+	dir        string
+	name       string
+	tileWidth  int
+	tileHeight int
+	spacing    int
+	margin     int
+	columns    int
+	tileCount  int
+	image      string
+	trans      string
+	tiles      []tiledTileDef
+}
+
+type tiledTileDef struct {
+	id         int
+	image      string
+	properties content.Properties
+}
+
+type tiledTileset struct {
+	firstGID int
+	tileset  *content.Tileset
+}
+
+// tiledTilesets finds the tileset of global tile IDs, sorted by first GID
+type tiledTilesets []tiledTileset
+
+func (sets tiledTilesets) tile(gid uint32) (content.Tile, error) {
+	id := int(gid & tiledGIDMask)
+	if id == 0 {
+		return content.Tile{}, nil
+	}
+
+	for i := len(sets) - 1; i >= 0; i-- {
+		if id < sets[i].firstGID {
+			continue
+		}
+
+		return content.Tile{
+			Tileset: sets[i].tileset,
+			ID:      id - sets[i].firstGID,
+			FlipH:   gid&tiledFlipH != 0,
+			FlipV:   gid&tiledFlipV != 0,
+			FlipD:   gid&tiledFlipD != 0,
+		}, nil
+	}
+
+	return content.Tile{}, fmt.Errorf("no tileset for tile %v", id)
+}
+
+// LoadTiledMap loads an orthogonal Tiled map saved as TMX or JSON.
+// Embedded and external tilesets, in either TSX or JSON, are loaded along
+// with their images. Tiled layer groups are flattened with their offsets,
+// opacity and visibility applied to each child, child layer names
+// are prefixed by the group name such as "group/layer".
+func (a *AssetLoader) LoadTiledMap(mapPath string) (*content.Tilemap, error) {
+	mapBytes, err := a.readFSFile(mapPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var tilemap *content.Tilemap
+
+	switch strings.ToLower(path.Ext(mapPath)) {
+	case ".tmx":
+		tilemap, err = a.loadTiledXML(mapPath, mapBytes)
+	case ".json", ".tmj":
+		tilemap, err = a.loadTiledJSON(mapPath, mapBytes)
+	default:
+		return nil, fmt.Errorf("loading tiled map %v: unknown format", mapPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("loading tiled map %v: %w", mapPath, err)
+	}
+
+	return tilemap, nil
+}
+
+// loadTiledTileset loads an external tileset saved as TSX or JSON
+func (a *AssetLoader) loadTiledTileset(tilesetPath string) (tiledTilesetDef, error) {
+	tilesetBytes, err := a.readFSFile(tilesetPath)
+	if err != nil {
+		return tiledTilesetDef{}, err
+	}
+
+	dir := path.Dir(tilesetPath)
+
+	switch strings.ToLower(path.Ext(tilesetPath)) {
+	case ".tsx":
+		return parseTiledXMLTileset(dir, tilesetBytes)
+	case ".json", ".tsj":
+		return parseTiledJSONTileset(dir, tilesetBytes)
+	default:
+		return tiledTilesetDef{}, fmt.Errorf("loading tileset %v: unknown format", tilesetPath)
+	}
+}
+
+// addTiledTileset adds a tileset to our map, loading it from
+// source if it is external instead of embedded.
+func (a *AssetLoader) addTiledTileset(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	firstGID int,
+	source string,
+	def tiledTilesetDef,
+) (tiledTilesets, error) {
+	if source != "" {
+		var err error
+
+		def, err = a.loadTiledTileset(path.Join(def.dir, source))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tileset, err := a.buildTiledTileset(def)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Tilesets = append(m.Tilesets, tileset)
+
+	return append(sets, tiledTileset{firstGID: firstGID, tileset: tileset}), nil
+}
+
+// buildTiledTileset cuts the tileset image into sprites, or for
+// collection tilesets loads an image for each tile.
+func (a *AssetLoader) buildTiledTileset(def tiledTilesetDef) (*content.Tileset, error) {
+	tileset := &content.Tileset{
+		Name:       def.name,
+		TileWidth:  def.tileWidth,
+		TileHeight: def.tileHeight,
+		Properties: make(map[int]content.Properties),
+	}
+
+	var options []ImageOption
+
+	if def.trans != "" {
+		key, err := parseHexColor(def.trans)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, ImageWithColorKey(key))
+	}
+
+	count := def.tileCount
+
+	for _, tile := range def.tiles {
+		if tile.id >= count {
+			count = tile.id + 1
+		}
+
+		if len(tile.properties) > 0 {
+			tileset.Properties[tile.id] = tile.properties
+		}
+	}
+
+	tileset.Sprites = make([]*content.Sprite, count)
+
+	if def.image != "" {
+		img, err := a.LoadImage(path.Join(def.dir, def.image), options...)
+		if err != nil {
+			return nil, err
+		}
+
+		copy(tileset.Sprites, gridSprites(
+			img,
+			image.Pt(def.tileWidth, def.tileHeight),
+			def.margin,
+			def.spacing,
+			def.columns,
+			def.tileCount,
+		))
+	}
+
+	for _, tile := range def.tiles {
+		if tile.image == "" {
+			continue
+		}
+
+		img, err := a.LoadImage(path.Join(def.dir, tile.image), options...)
+		if err != nil {
+			return nil, err
+		}
+
+		tileset.Sprites[tile.id] = &content.Sprite{Image: img}
+	}
+
+	return tileset, nil
+}
+
+// gridSprites cuts count sprites of size from img, going left to right then
+// top to bottom, with margin around the image and spacing between sprites.
+func gridSprites(
+	img *ebiten.Image,
+	size image.Point,
+	margin, spacing, columns, count int,
+) []*content.Sprite {
+	if columns <= 0 {
+		columns = 1
+	}
+
+	sprites := make([]*content.Sprite, count)
+
+	for i := range sprites {
+		x := margin + (i%columns)*(size.X+spacing)
+		y := margin + (i/columns)*(size.Y+spacing)
+		rect := image.Rect(x, y, x+size.X, y+size.Y)
+
+		sprites[i] = &content.Sprite{
+			Image: img.SubImage(rect).(*ebiten.Image),
+			Page:  img,
+		}
+	}
+
+	return sprites
+}
+
+func decodeTiledData(data, encoding, compression string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		fields := strings.Split(data, ",")
+		gids := make([]uint32, 0, len(fields))
+
+		for _, field := range fields {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("parsing tile data: %w", err)
+			}
+
+			gids = append(gids, uint32(gid))
+		}
+
+		return gids, nil
+	case "base64":
+		return decodeTiledBase64(strings.TrimSpace(data), compression)
+	default:
+		return nil, fmt.Errorf("unsupported tile data encoding %q", encoding)
+	}
+}
+
+func decodeTiledBase64(data, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding tile data: %w", err)
+	}
+
+	var reader io.Reader = bytes.NewReader(raw)
+
+	switch compression {
+	case "":
+	case "gzip":
+		reader, err = gzip.NewReader(reader)
+	case "zlib":
+		reader, err = zlib.NewReader(reader)
+	default:
+		return nil, fmt.Errorf("unsupported tile data compression %q", compression)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decompressing tile data: %w", err)
+	}
+
+	raw, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing tile data: %w", err)
+	}
+
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+
+	return gids, nil
+}
+
+// tiledLayerState is the combined state of the groups a layer is in
+type tiledLayerState struct {
+	name    string
+	offsetX float64
+	offsetY float64
+	opacity float64
+	visible bool
+}
+
+func (s tiledLayerState) child(
+	name string,
+	offsetX, offsetY, opacity float64,
+	visible bool,
+) tiledLayerState {
+	return tiledLayerState{
+		name:    s.name + name,
+		offsetX: s.offsetX + offsetX,
+		offsetY: s.offsetY + offsetY,
+		opacity: s.opacity * opacity,
+		visible: s.visible && visible,
+	}
+}
+
+func newTiledTileLayer(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	state tiledLayerState,
+	width, height int,
+	gids []uint32,
+	properties content.Properties,
+) (*content.TileLayer, error) {
+	if len(gids) != width*height {
+		return nil, fmt.Errorf(
+			"layer %v has %v tiles, expected %v",
+			state.name, len(gids), width*height,
+		)
+	}
+
+	layer := &content.TileLayer{
+		Name:       state.name,
+		Width:      width,
+		Height:     height,
+		TileWidth:  m.TileWidth,
+		TileHeight: m.TileHeight,
+		OffsetX:    state.offsetX,
+		OffsetY:    state.offsetY,
+		Opacity:    state.opacity,
+		Visible:    state.visible,
+		Tiles:      make([]content.Tile, len(gids)),
+		Properties: properties,
+	}
+
+	for i, gid := range gids {
+		tile, err := sets.tile(gid)
+		if err != nil {
+			return nil, err
+		}
+
+		layer.Tiles[i] = tile
+	}
+
+	return layer, nil
+}
+
+func newTiledObjectLayer(
+	state tiledLayerState,
+	properties content.Properties,
+) *content.ObjectLayer {
+	return &content.ObjectLayer{
+		Name:       state.name,
+		OffsetX:    state.offsetX,
+		OffsetY:    state.offsetY,
+		Visible:    state.visible,
+		Properties: properties,
+	}
+}
+
+// tiledObjectShape returns the shape of an object from the flags set by Tiled
+func tiledObjectShape(gid uint32, ellipse, point, polygon, polyline bool) content.ObjectShape {
+	switch {
+	case gid != 0:
+		return content.ObjectTile
+	case ellipse:
+		return content.ObjectEllipse
+	case point:
+		return content.ObjectPoint
+	case polygon:
+		return content.ObjectPolygon
+	case polyline:
+		return content.ObjectPolyline
+	default:
+		return content.ObjectRectangle
+	}
+}
+
+func parseTiledProperty(propType, value string) (any, error) {
+	switch propType {
+	case "int", "object":
+		return strconv.Atoi(value)
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	case "bool":
+		return strconv.ParseBool(value)
+	case "color":
+		if value == "" {
+			return color.NRGBA{}, nil
+		}
+
+		return parseHexColor(value)
+	default:
+		return value, nil
+	}
+}
+
+// parseHexColor parses straight alpha colors written as #AARRGGBB,
+// #RRGGBB or RRGGBB
+func parseHexColor(value string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 6 {
+		hex = "ff" + hex
+	}
+
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("parsing color %q: invalid length", value)
+	}
+
+	argb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("parsing color %q: %w", value, err)
+	}
+
+	return color.NRGBA{
+		A: uint8(argb >> 24),
+		R: uint8(argb >> 16),
+		G: uint8(argb >> 8),
+		B: uint8(argb),
+	}, nil
+}
@@ -0,0 +1,224 @@
+package igloo_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/miniscruff/igloo"
+	"github.com/miniscruff/igloo/content"
+)
+
+const tiledTSX = `<tileset name="ground" tilewidth="2" tileheight="2" tilecount="2" columns="2">
+ <image source="tiles.png" width="4" height="2"/>
+ <tile id="1"><properties><property name="solid" type="bool" value="true"/></properties></tile>
+</tileset>`
+
+const tiledTSJ = `{"name": "ground", "tilewidth": 2, "tileheight": 2, "tilecount": 2,
+ "columns": 2, "image": "tiles.png",
+ "tiles": [{"id": 1, "properties": [{"name": "solid", "type": "bool", "value": true}]}]}`
+
+const tiledTMX = `<map orientation="orthogonal" width="2" height="2" tilewidth="2" tileheight="2">
+ <properties>
+  <property name="music" value="forest"/>
+  <property name="fog" type="color" value="#80ff0000"/>
+ </properties>
+ %v
+ <group name="world" offsetx="4">
+  <layer name="ground" width="2" height="2">%v</layer>
+ </group>
+ <objectgroup name="spawns">
+  <object id="1" name="player" type="spawn" x="1" y="2" width="4" height="4">
+   <properties><property name="health" type="int" value="3"/></properties>
+  </object>
+  <object id="2" x="0" y="0" width="2" height="2"><ellipse/></object>
+  <object id="3" x="0" y="0"><polygon points="0,0 2,0 2,2"/></object>
+  <object id="4" gid="2147483650" x="0" y="2" width="2" height="2"/>
+ </objectgroup>
+</map>`
+
+const tiledJSON = `{"orientation": "orthogonal", "width": 2, "height": 2,
+ "tilewidth": 2, "tileheight": 2,
+ "properties": [{"name": "music", "type": "string", "value": "forest"},
+  {"name": "fog", "type": "color", "value": "#80ff0000"}],
+ "tilesets": [%v],
+ "layers": [
+  {"type": "group", "name": "world", "offsetx": 4, "layers": [
+   {"type": "tilelayer", "name": "ground", "width": 2, "height": 2, %v}
+  ]},
+  {"type": "objectgroup", "name": "spawns", "objects": [
+   {"id": 1, "name": "player", "type": "spawn", "x": 1, "y": 2, "width": 4, "height": 4,
+    "properties": [{"name": "health", "type": "int", "value": 3}]},
+   {"id": 2, "x": 0, "y": 0, "width": 2, "height": 2, "ellipse": true},
+   {"id": 3, "x": 0, "y": 0, "polygon": [{"x": 0, "y": 0}, {"x": 2, "y": 0}, {"x": 2, "y": 2}]},
+   {"id": 4, "gid": 2147483650, "x": 0, "y": 2, "width": 2, "height": 2}
+  ]}
+ ]}`
+
+// tiledGIDs has a flipped second tile in the top right
+var tiledGIDs = []uint32{1, 2 | 0x80000000, 0, 2}
+
+func tiledTilesPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func tiledZlibData(t *testing.T) string {
+	var raw, compressed bytes.Buffer
+
+	for _, gid := range tiledGIDs {
+		_ = binary.Write(&raw, binary.LittleEndian, gid)
+	}
+
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	w.Close()
+
+	return base64.StdEncoding.EncodeToString(compressed.Bytes())
+}
+
+func TestLoadTiledMap(t *testing.T) {
+	embeddedTSX := `<tileset firstgid="1" name="ground" tilewidth="2" tileheight="2"
+ tilecount="2" columns="2"><image source="tiles.png"/>
+ <tile id="1"><properties><property name="solid" type="bool" value="true"/></properties></tile>
+</tileset>`
+	embeddedTSJ := `{"firstgid": 1, "name": "ground", "tilewidth": 2, "tileheight": 2,
+ "tilecount": 2, "columns": 2, "image": "tiles.png",
+ "tiles": [{"id": 1, "properties": [{"name": "solid", "type": "bool", "value": true}]}]}`
+	zlibData := tiledZlibData(t)
+
+	fsys := fstest.MapFS{
+		"assets/tiles.png":  {Data: tiledTilesPNG(t)},
+		"assets/ground.tsx": {Data: []byte(tiledTSX)},
+		"assets/ground.tsj": {Data: []byte(tiledTSJ)},
+		"assets/csv.tmx": {Data: []byte(fmt.Sprintf(
+			tiledTMX, embeddedTSX, `<data encoding="csv">1,2147483650,0,2</data>`,
+		))},
+		"assets/zlib.tmx": {Data: []byte(fmt.Sprintf(
+			tiledTMX,
+			`<tileset firstgid="1" source="ground.tsx"/>`,
+			`<data encoding="base64" compression="zlib">`+zlibData+`</data>`,
+		))},
+		"assets/array.json": {Data: []byte(fmt.Sprintf(
+			tiledJSON, embeddedTSJ, `"data": [1, 2147483650, 0, 2]`,
+		))},
+		"assets/base64.tmj": {Data: []byte(fmt.Sprintf(
+			tiledJSON,
+			`{"firstgid": 1, "source": "ground.tsj"}`,
+			`"encoding": "base64", "compression": "zlib", "data": "`+zlibData+`"`,
+		))},
+		"assets/hex.tmx": {Data: []byte(`<map orientation="hexagonal"></map>`)},
+	}
+
+	tests := map[string]struct {
+		path      string
+		expectErr bool
+	}{
+		"tmx csv embedded tileset":  {path: "csv.tmx"},
+		"tmx zlib external tileset": {path: "zlib.tmx"},
+		"json array embedded":       {path: "array.json"},
+		"json base64 external":      {path: "base64.tmj"},
+		"unsupported orientation":   {path: "hex.tmx", expectErr: true},
+		"missing map":               {path: "missing.tmx", expectErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := igloo.NewAssetLoader(fsys, "assets")
+
+			m, err := loader.LoadTiledMap(tc.path)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkTiledMap(t, m)
+		})
+	}
+}
+
+func checkTiledMap(t *testing.T, m *content.Tilemap) {
+	if m.Properties.String("music") != "forest" {
+		t.Fatalf("expected: forest, got: %v", m.Properties["music"])
+	}
+
+	// tiled colors have straight alpha
+	if fog := m.Properties["fog"]; fog != (color.NRGBA{R: 0xff, A: 0x80}) {
+		t.Fatalf("expected: half transparent red, got: %v", fog)
+	}
+
+	layer := m.TileLayer("world/ground")
+	if layer == nil {
+		t.Fatalf("expected layer world/ground, got: %v", m.TileLayers)
+	}
+
+	if layer.OffsetX != 4 {
+		t.Fatalf("expected: 4, got: %v", layer.OffsetX)
+	}
+
+	flipped := layer.At(1, 0)
+	if flipped.ID != 1 || !flipped.FlipH || flipped.FlipV || flipped.FlipD {
+		t.Fatalf("expected: flipped tile 1, got: %+v", flipped)
+	}
+
+	if !flipped.Properties().Bool("solid") {
+		t.Fatalf("expected: solid tile, got: %v", flipped.Properties())
+	}
+
+	if !layer.At(0, 1).Empty() || layer.At(0, 0).Sprite() == nil {
+		t.Fatal("expected: empty bottom left and a top left sprite")
+	}
+
+	spawns := m.ObjectLayer("spawns")
+	if spawns == nil || len(spawns.Objects) != 4 {
+		t.Fatalf("expected: 4 objects, got: %v", spawns)
+	}
+
+	player := spawns.Objects[0]
+	if player.Name != "player" || player.Type != "spawn" || player.Properties.Int("health") != 3 {
+		t.Fatalf("expected: player spawn with 3 health, got: %+v", player)
+	}
+
+	shapes := []content.ObjectShape{
+		content.ObjectRectangle,
+		content.ObjectEllipse,
+		content.ObjectPolygon,
+		content.ObjectTile,
+	}
+
+	for i, shape := range shapes {
+		if spawns.Objects[i].Shape != shape {
+			t.Fatalf("expected: %v, got: %v", shape, spawns.Objects[i].Shape)
+		}
+	}
+
+	if len(spawns.Objects[2].Points) != 3 {
+		t.Fatalf("expected: 3 points, got: %v", spawns.Objects[2].Points)
+	}
+
+	if tile := spawns.Objects[3].Tile; tile.ID != 1 || !tile.FlipH {
+		t.Fatalf("expected: flipped tile 1, got: %+v", tile)
+	}
+}
@@ -0,0 +1,358 @@
+package igloo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+type tiledJSONProperty struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+func parseTiledJSONProperties(properties []tiledJSONProperty) (content.Properties, error) {
+	if len(properties) == 0 {
+		return nil, nil
+	}
+
+	props := make(content.Properties, len(properties))
+
+	for _, prop := range properties {
+		switch value := prop.Value.(type) {
+		case string:
+			parsed, err := parseTiledProperty(prop.Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("parsing property %v: %w", prop.Name, err)
+			}
+
+			props[prop.Name] = parsed
+		case float64:
+			if prop.Type == "int" || prop.Type == "object" {
+				props[prop.Name] = int(value)
+			} else {
+				props[prop.Name] = value
+			}
+		default:
+			// bools and class values are kept as decoded
+			props[prop.Name] = value
+		}
+	}
+
+	return props, nil
+}
+
+type tiledJSONTile struct {
+	ID         int                 `json:"id"`
+	Image      string              `json:"image"`
+	Properties []tiledJSONProperty `json:"properties"`
+}
+
+type tiledJSONTileset struct {
+	FirstGID         int             `json:"firstgid"`
+	Source           string          `json:"source"`
+	Name             string          `json:"name"`
+	TileWidth        int             `json:"tilewidth"`
+	TileHeight       int             `json:"tileheight"`
+	Spacing          int             `json:"spacing"`
+	Margin           int             `json:"margin"`
+	TileCount        int             `json:"tilecount"`
+	Columns          int             `json:"columns"`
+	Image            string          `json:"image"`
+	TransparentColor string          `json:"transparentcolor"`
+	Tiles            []tiledJSONTile `json:"tiles"`
+}
+
+func (ts tiledJSONTileset) def(dir string) (tiledTilesetDef, error) {
+	def := tiledTilesetDef{
+		dir:        dir,
+		name:       ts.Name,
+		tileWidth:  ts.TileWidth,
+		tileHeight: ts.TileHeight,
+		spacing:    ts.Spacing,
+		margin:     ts.Margin,
+		columns:    ts.Columns,
+		tileCount:  ts.TileCount,
+		image:      ts.Image,
+		trans:      ts.TransparentColor,
+		tiles:      make([]tiledTileDef, len(ts.Tiles)),
+	}
+
+	for i, tile := range ts.Tiles {
+		props, err := parseTiledJSONProperties(tile.Properties)
+		if err != nil {
+			return def, fmt.Errorf("tile %v: %w", tile.ID, err)
+		}
+
+		def.tiles[i] = tiledTileDef{
+			id:         tile.ID,
+			image:      tile.Image,
+			properties: props,
+		}
+	}
+
+	return def, nil
+}
+
+type tiledJSONObject struct {
+	ID         int                 `json:"id"`
+	Name       string              `json:"name"`
+	Type       string              `json:"type"`
+	Class      string              `json:"class"`
+	X          float64             `json:"x"`
+	Y          float64             `json:"y"`
+	Width      float64             `json:"width"`
+	Height     float64             `json:"height"`
+	Rotation   float64             `json:"rotation"`
+	GID        uint32              `json:"gid"`
+	Visible    *bool               `json:"visible"`
+	Ellipse    bool                `json:"ellipse"`
+	Point      bool                `json:"point"`
+	Polygon    []mathf.Vec2        `json:"polygon"`
+	Polyline   []mathf.Vec2        `json:"polyline"`
+	Properties []tiledJSONProperty `json:"properties"`
+}
+
+func (o tiledJSONObject) object(sets tiledTilesets) (*content.MapObject, error) {
+	obj := &content.MapObject{
+		ID:       o.ID,
+		Name:     o.Name,
+		Type:     o.Type,
+		X:        o.X,
+		Y:        o.Y,
+		Width:    o.Width,
+		Height:   o.Height,
+		Rotation: o.Rotation,
+		Visible:  o.Visible == nil || *o.Visible,
+		Shape: tiledObjectShape(
+			o.GID, o.Ellipse, o.Point, o.Polygon != nil, o.Polyline != nil,
+		),
+		Points: o.Polygon,
+	}
+
+	if obj.Type == "" {
+		obj.Type = o.Class
+	}
+
+	if o.Polyline != nil {
+		obj.Points = o.Polyline
+	}
+
+	var err error
+
+	if obj.Tile, err = sets.tile(o.GID); err != nil {
+		return nil, err
+	}
+
+	if obj.Properties, err = parseTiledJSONProperties(o.Properties); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+type tiledJSONLayer struct {
+	Type        string              `json:"type"`
+	Name        string              `json:"name"`
+	Width       int                 `json:"width"`
+	Height      int                 `json:"height"`
+	Opacity     *float64            `json:"opacity"`
+	Visible     *bool               `json:"visible"`
+	OffsetX     float64             `json:"offsetx"`
+	OffsetY     float64             `json:"offsety"`
+	Encoding    string              `json:"encoding"`
+	Compression string              `json:"compression"`
+	Data        json.RawMessage     `json:"data"`
+	Chunks      json.RawMessage     `json:"chunks"`
+	Objects     []tiledJSONObject   `json:"objects"`
+	Layers      []tiledJSONLayer    `json:"layers"`
+	Properties  []tiledJSONProperty `json:"properties"`
+}
+
+func (l tiledJSONLayer) state(parent tiledLayerState) tiledLayerState {
+	opacity := 1.0
+	if l.Opacity != nil {
+		opacity = *l.Opacity
+	}
+
+	return parent.child(l.Name, l.OffsetX, l.OffsetY, opacity, l.Visible == nil || *l.Visible)
+}
+
+// gids decodes tile data stored as either an array or a base64 string
+func (l tiledJSONLayer) gids() ([]uint32, error) {
+	if len(l.Chunks) > 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	if l.Encoding != "base64" {
+		var gids []uint32
+		if err := json.Unmarshal(l.Data, &gids); err != nil {
+			return nil, fmt.Errorf("parsing tile data: %w", err)
+		}
+
+		return gids, nil
+	}
+
+	data, err := strconv.Unquote(string(l.Data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing tile data: %w", err)
+	}
+
+	return decodeTiledBase64(data, l.Compression)
+}
+
+type tiledJSONMap struct {
+	Orientation string              `json:"orientation"`
+	Width       int                 `json:"width"`
+	Height      int                 `json:"height"`
+	TileWidth   int                 `json:"tilewidth"`
+	TileHeight  int                 `json:"tileheight"`
+	Infinite    bool                `json:"infinite"`
+	Tilesets    []tiledJSONTileset  `json:"tilesets"`
+	Layers      []tiledJSONLayer    `json:"layers"`
+	Properties  []tiledJSONProperty `json:"properties"`
+}
+
+func parseTiledJSONTileset(dir string, data []byte) (tiledTilesetDef, error) {
+	var ts tiledJSONTileset
+
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return tiledTilesetDef{}, fmt.Errorf("parsing tileset: %w", err)
+	}
+
+	return ts.def(dir)
+}
+
+func (a *AssetLoader) loadTiledJSON(mapPath string, data []byte) (*content.Tilemap, error) {
+	var file tiledJSONMap
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	if file.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported orientation %q", file.Orientation)
+	}
+
+	if file.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	m := &content.Tilemap{
+		Width:      file.Width,
+		Height:     file.Height,
+		TileWidth:  file.TileWidth,
+		TileHeight: file.TileHeight,
+	}
+
+	var sets tiledTilesets
+
+	dir := path.Dir(mapPath)
+
+	for _, ts := range file.Tilesets {
+		def, err := ts.def(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		sets, err = a.addTiledTileset(m, sets, ts.FirstGID, ts.Source, def)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root := tiledLayerState{opacity: 1, visible: true}
+	if err := addTiledJSONLayers(m, sets, root, file.Layers); err != nil {
+		return nil, err
+	}
+
+	props, err := parseTiledJSONProperties(file.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Properties = props
+
+	return m, nil
+}
+
+func (l tiledJSONLayer) tileLayer(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	state tiledLayerState,
+) (*content.TileLayer, error) {
+	props, err := parseTiledJSONProperties(l.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	gids, err := l.gids()
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	return newTiledTileLayer(m, sets, state, l.Width, l.Height, gids, props)
+}
+
+func (l tiledJSONLayer) objectLayer(
+	sets tiledTilesets,
+	state tiledLayerState,
+) (*content.ObjectLayer, error) {
+	props, err := parseTiledJSONProperties(l.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	layer := newTiledObjectLayer(state, props)
+
+	for _, o := range l.Objects {
+		obj, err := o.object(sets)
+		if err != nil {
+			return nil, fmt.Errorf("layer %v object %v: %w", state.name, o.ID, err)
+		}
+
+		layer.Objects = append(layer.Objects, obj)
+	}
+
+	return layer, nil
+}
+
+func addTiledJSONLayers(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	parent tiledLayerState,
+	layers []tiledJSONLayer,
+) error {
+	for _, l := range layers {
+		state := l.state(parent)
+
+		switch l.Type {
+		case "tilelayer":
+			layer, err := l.tileLayer(m, sets, state)
+			if err != nil {
+				return err
+			}
+
+			m.TileLayers = append(m.TileLayers, layer)
+		case "objectgroup":
+			layer, err := l.objectLayer(sets, state)
+			if err != nil {
+				return err
+			}
+
+			m.ObjectLayers = append(m.ObjectLayers, layer)
+		case "group":
+			state.name += "/"
+			if err := addTiledJSONLayers(m, sets, state, l.Layers); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,403 @@
+package igloo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/miniscruff/igloo/content"
+	"github.com/miniscruff/igloo/mathf"
+)
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	// multiline string values are stored as text instead of an attribute
+	Text string `xml:",chardata"`
+}
+
+type tmxProperties struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+func (p tmxProperties) parse() (content.Properties, error) {
+	if len(p.Properties) == 0 {
+		return nil, nil
+	}
+
+	props := make(content.Properties, len(p.Properties))
+
+	for _, prop := range p.Properties {
+		value := prop.Value
+		if value == "" {
+			value = prop.Text
+		}
+
+		parsed, err := parseTiledProperty(prop.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing property %v: %w", prop.Name, err)
+		}
+
+		props[prop.Name] = parsed
+	}
+
+	return props, nil
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Trans  string `xml:"trans,attr"`
+}
+
+type tmxTile struct {
+	ID         int           `xml:"id,attr"`
+	Image      tmxImage      `xml:"image"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+type tmxTileset struct {
+	FirstGID   int       `xml:"firstgid,attr"`
+	Source     string    `xml:"source,attr"`
+	Name       string    `xml:"name,attr"`
+	TileWidth  int       `xml:"tilewidth,attr"`
+	TileHeight int       `xml:"tileheight,attr"`
+	Spacing    int       `xml:"spacing,attr"`
+	Margin     int       `xml:"margin,attr"`
+	TileCount  int       `xml:"tilecount,attr"`
+	Columns    int       `xml:"columns,attr"`
+	Image      tmxImage  `xml:"image"`
+	Tiles      []tmxTile `xml:"tile"`
+}
+
+func (ts tmxTileset) def(dir string) (tiledTilesetDef, error) {
+	def := tiledTilesetDef{
+		dir:        dir,
+		name:       ts.Name,
+		tileWidth:  ts.TileWidth,
+		tileHeight: ts.TileHeight,
+		spacing:    ts.Spacing,
+		margin:     ts.Margin,
+		columns:    ts.Columns,
+		tileCount:  ts.TileCount,
+		image:      ts.Image.Source,
+		trans:      ts.Image.Trans,
+		tiles:      make([]tiledTileDef, len(ts.Tiles)),
+	}
+
+	for i, tile := range ts.Tiles {
+		props, err := tile.Properties.parse()
+		if err != nil {
+			return def, fmt.Errorf("tile %v: %w", tile.ID, err)
+		}
+
+		def.tiles[i] = tiledTileDef{
+			id:         tile.ID,
+			image:      tile.Image.Source,
+			properties: props,
+		}
+	}
+
+	return def, nil
+}
+
+type tmxDataTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxData struct {
+	Encoding    string        `xml:"encoding,attr"`
+	Compression string        `xml:"compression,attr"`
+	Tiles       []tmxDataTile `xml:"tile"`
+	Chunks      []struct{}    `xml:"chunk"`
+	Text        string        `xml:",chardata"`
+}
+
+func (d tmxData) gids() ([]uint32, error) {
+	if len(d.Chunks) > 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	if d.Encoding == "" {
+		gids := make([]uint32, len(d.Tiles))
+		for i, tile := range d.Tiles {
+			gids[i] = tile.GID
+		}
+
+		return gids, nil
+	}
+
+	return decodeTiledData(d.Text, d.Encoding, d.Compression)
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+func (p *tmxPoints) parse() ([]mathf.Vec2, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	fields := strings.Fields(p.Points)
+	points := make([]mathf.Vec2, len(fields))
+
+	for i, field := range fields {
+		x, y, _ := strings.Cut(field, ",")
+
+		px, err := strconv.ParseFloat(x, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing points: %w", err)
+		}
+
+		py, err := strconv.ParseFloat(y, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing points: %w", err)
+		}
+
+		points[i] = mathf.Vec2{X: px, Y: py}
+	}
+
+	return points, nil
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+func (o tmxObject) object(sets tiledTilesets) (*content.MapObject, error) {
+	obj := &content.MapObject{
+		ID:       o.ID,
+		Name:     o.Name,
+		Type:     o.Type,
+		X:        o.X,
+		Y:        o.Y,
+		Width:    o.Width,
+		Height:   o.Height,
+		Rotation: o.Rotation,
+		Visible:  o.Visible == nil || *o.Visible != 0,
+		Shape: tiledObjectShape(
+			o.GID, o.Ellipse != nil, o.Point != nil, o.Polygon != nil, o.Polyline != nil,
+		),
+	}
+
+	if obj.Type == "" {
+		obj.Type = o.Class
+	}
+
+	var err error
+
+	if obj.Tile, err = sets.tile(o.GID); err != nil {
+		return nil, err
+	}
+
+	if obj.Points, err = o.Polygon.parse(); err != nil {
+		return nil, err
+	}
+
+	if o.Polyline != nil {
+		if obj.Points, err = o.Polyline.parse(); err != nil {
+			return nil, err
+		}
+	}
+
+	if obj.Properties, err = o.Properties.parse(); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// tmxLayer is any of a tile layer, object group or group
+// as they have to be read in order.
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Data       tmxData       `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Layers     []tmxLayer    `xml:",any"`
+}
+
+func (l tmxLayer) state(parent tiledLayerState) tiledLayerState {
+	opacity := 1.0
+	if l.Opacity != nil {
+		opacity = *l.Opacity
+	}
+
+	return parent.child(l.Name, l.OffsetX, l.OffsetY, opacity, l.Visible == nil || *l.Visible != 0)
+}
+
+type tmxMap struct {
+	Orientation string        `xml:"orientation,attr"`
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   int           `xml:"tilewidth,attr"`
+	TileHeight  int           `xml:"tileheight,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Properties  tmxProperties `xml:"properties"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	Layers      []tmxLayer    `xml:",any"`
+}
+
+func parseTiledXMLTileset(dir string, data []byte) (tiledTilesetDef, error) {
+	var ts tmxTileset
+
+	if err := xml.Unmarshal(data, &ts); err != nil {
+		return tiledTilesetDef{}, fmt.Errorf("parsing tileset: %w", err)
+	}
+
+	return ts.def(dir)
+}
+
+func (a *AssetLoader) loadTiledXML(mapPath string, data []byte) (*content.Tilemap, error) {
+	var file tmxMap
+
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	if file.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported orientation %q", file.Orientation)
+	}
+
+	if file.Infinite != 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	m := &content.Tilemap{
+		Width:      file.Width,
+		Height:     file.Height,
+		TileWidth:  file.TileWidth,
+		TileHeight: file.TileHeight,
+	}
+
+	var sets tiledTilesets
+
+	dir := path.Dir(mapPath)
+
+	for _, ts := range file.Tilesets {
+		def, err := ts.def(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		sets, err = a.addTiledTileset(m, sets, ts.FirstGID, ts.Source, def)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root := tiledLayerState{opacity: 1, visible: true}
+	if err := addTiledXMLLayers(m, sets, root, file.Layers); err != nil {
+		return nil, err
+	}
+
+	props, err := file.Properties.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	m.Properties = props
+
+	return m, nil
+}
+
+func (l tmxLayer) tileLayer(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	state tiledLayerState,
+) (*content.TileLayer, error) {
+	props, err := l.Properties.parse()
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	gids, err := l.Data.gids()
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	return newTiledTileLayer(m, sets, state, l.Width, l.Height, gids, props)
+}
+
+func (l tmxLayer) objectLayer(
+	sets tiledTilesets,
+	state tiledLayerState,
+) (*content.ObjectLayer, error) {
+	props, err := l.Properties.parse()
+	if err != nil {
+		return nil, fmt.Errorf("layer %v: %w", state.name, err)
+	}
+
+	layer := newTiledObjectLayer(state, props)
+
+	for _, o := range l.Objects {
+		obj, err := o.object(sets)
+		if err != nil {
+			return nil, fmt.Errorf("layer %v object %v: %w", state.name, o.ID, err)
+		}
+
+		layer.Objects = append(layer.Objects, obj)
+	}
+
+	return layer, nil
+}
+
+func addTiledXMLLayers(
+	m *content.Tilemap,
+	sets tiledTilesets,
+	parent tiledLayerState,
+	layers []tmxLayer,
+) error {
+	for _, l := range layers {
+		state := l.state(parent)
+
+		switch l.XMLName.Local {
+		case "layer":
+			layer, err := l.tileLayer(m, sets, state)
+			if err != nil {
+				return err
+			}
+
+			m.TileLayers = append(m.TileLayers, layer)
+		case "objectgroup":
+			layer, err := l.objectLayer(sets, state)
+			if err != nil {
+				return err
+			}
+
+			m.ObjectLayers = append(m.ObjectLayers, layer)
+		case "group":
+			state.name += "/"
+			if err := addTiledXMLLayers(m, sets, state, l.Layers); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
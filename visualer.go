@@ -1,6 +1,8 @@
 package igloo
 
 import (
+	"sort"
+
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"github.com/miniscruff/igloo/mathf"
@@ -13,10 +15,19 @@ type Visualer struct {
 	Drawer
 	NativeSizer
 
-	Parent   *Visualer
+	Parent *Visualer
+	// Children are read only, use InsertChild and RemoveChild to change them
+	// as our draw order is cached until a child is inserted or removed.
 	Children []*Visualer
 	visible  bool
 
+	zIndex          int
+	layer           string
+	drawParentFirst bool
+	drawOrder       []*Visualer
+	isOrderDirty    bool
+	subtreeChanged  bool
+
 	nowVisible           bool
 	forcedTransformDirty bool
 	forcedDirty          bool
@@ -25,6 +36,113 @@ type Visualer struct {
 func (v *Visualer) InsertChild(child *Visualer) {
 	v.Children = append(v.Children, child)
 	child.Parent = v
+	v.isOrderDirty = true
+	child.markChanged()
+}
+
+// RemoveChild removes a child keeping the order of our other children,
+// nothing happens if child is not one of ours.
+func (v *Visualer) RemoveChild(child *Visualer) {
+	for i, c := range v.Children {
+		if c != child {
+			continue
+		}
+
+		child.markChanged()
+		copy(v.Children[i:], v.Children[i+1:])
+		v.Children[len(v.Children)-1] = nil
+		v.Children = v.Children[:len(v.Children)-1]
+		child.Parent = nil
+		v.isOrderDirty = true
+
+		return
+	}
+}
+
+// markChanged tells every ancestor something in their subtree changed
+func (v *Visualer) markChanged() {
+	for parent := v.Parent; parent != nil; parent = parent.Parent {
+		parent.subtreeChanged = true
+	}
+}
+
+// SubtreeChanged returns whether or not any descendant was added, shown,
+// hidden, dirty or moved since the last ClearSubtreeChanged.
+// Containers caching their children use this to know when to redraw.
+func (v *Visualer) SubtreeChanged() bool {
+	return v.subtreeChanged
+}
+
+func (v *Visualer) ClearSubtreeChanged() {
+	v.subtreeChanged = false
+}
+
+// ZIndex returns our draw order among our siblings
+func (v *Visualer) ZIndex() int {
+	return v.zIndex
+}
+
+// SetZIndex sets our draw order among our siblings, higher values are
+// drawn on top and siblings with the same z index keep their child order.
+func (v *Visualer) SetZIndex(zIndex int) {
+	if v.zIndex == zIndex {
+		return
+	}
+
+	v.zIndex = zIndex
+
+	if v.Parent != nil {
+		v.Parent.isOrderDirty = true
+	}
+
+	v.markChanged()
+}
+
+// Layer returns the render layer we are drawn in, empty uses our parents layer
+func (v *Visualer) Layer() string {
+	return v.layer
+}
+
+// SetLayer sets the render layer we and our children are drawn in
+// when drawing with RenderLayers, see RenderLayers.
+func (v *Visualer) SetLayer(layer string) {
+	if v.layer == layer {
+		return
+	}
+
+	v.layer = layer
+	v.markChanged()
+}
+
+// DrawParentFirst returns whether or not we are drawn before our children
+func (v *Visualer) DrawParentFirst() bool {
+	return v.drawParentFirst
+}
+
+// SetDrawParentFirst draws us before our children so they appear on top,
+// by default children are drawn first and appear behind us.
+func (v *Visualer) SetDrawParentFirst(parentFirst bool) {
+	if v.drawParentFirst == parentFirst {
+		return
+	}
+
+	v.drawParentFirst = parentFirst
+	v.markChanged()
+}
+
+// DrawOrder returns our children sorted by z index
+func (v *Visualer) DrawOrder() []*Visualer {
+	if !v.isOrderDirty && len(v.drawOrder) == len(v.Children) {
+		return v.drawOrder
+	}
+
+	v.drawOrder = append(v.drawOrder[:0], v.Children...)
+	sort.SliceStable(v.drawOrder, func(i, j int) bool {
+		return v.drawOrder[i].zIndex < v.drawOrder[j].zIndex
+	})
+	v.isOrderDirty = false
+
+	return v.drawOrder
 }
 
 // ForceDirty can be called if you really need to force a rebuild of the transform
@@ -44,6 +162,7 @@ func (v *Visualer) SetVisible(state bool) {
 
 	v.visible = state
 	v.nowVisible = state
+	v.markChanged()
 }
 
 func (v *Visualer) Layout(root, parent *mathf.Transform) {
@@ -58,6 +177,11 @@ func (v *Visualer) Layout(root, parent *mathf.Transform) {
 		}
 	}
 
+	if v.nowVisible || v.forcedDirty || v.forcedTransformDirty ||
+		v.Transform.IsDirty() || v.Dirtier.IsDirty() {
+		v.markChanged()
+	}
+
 	// if our transform is dirty, or our parent forced it
 	// update our children as well
 	if v.Transform.IsDirty() || v.forcedTransformDirty {
@@ -87,6 +211,7 @@ func (v *Visualer) Layout(root, parent *mathf.Transform) {
 
 	if v.nowVisible || v.forcedDirty || v.forcedTransformDirty {
 		v.Transform.Build(parent)
+		v.buildForBounds(parent)
 	}
 
 	// needs to be after we try and build
@@ -101,14 +226,71 @@ func (v *Visualer) Layout(root, parent *mathf.Transform) {
 	v.forcedTransformDirty = false
 }
 
+// buildForBounds lets a BoundsWatcher update our native size from the
+// bounds we were just built with, building again when it changes.
+func (v *Visualer) buildForBounds(parent *mathf.Transform) {
+	watcher, ok := v.Dirtier.(BoundsWatcher)
+	if !ok || !watcher.BoundsBuilt(v.Transform.Bounds()) {
+		return
+	}
+
+	nativeWidth, nativeHeight := v.NativeSize()
+	v.Transform.SetNaturalWidth(nativeWidth)
+	v.Transform.SetNaturalHeight(nativeHeight)
+	v.Transform.Build(parent)
+	watcher.BoundsBuilt(v.Transform.Bounds())
+}
+
 func (v *Visualer) Draw(dest *ebiten.Image) {
 	if !v.visible {
 		return
 	}
 
-	for _, child := range v.Children {
+	if childDrawer, ok := v.Drawer.(ChildDrawer); ok {
+		childDrawer.DrawWithChildren(dest)
+		return
+	}
+
+	if v.drawParentFirst {
+		v.Drawer.Draw(dest)
+	}
+
+	for _, child := range v.DrawOrder() {
 		child.Draw(dest)
 	}
 
-	v.Drawer.Draw(dest)
+	if !v.drawParentFirst {
+		v.Drawer.Draw(dest)
+	}
+}
+
+// HitTest returns the topmost visible visual whose bounds contain a world
+// space point, or nil. Children are checked in reverse draw order and
+// visuals with a HitFilter can prune their whole subtree.
+func (v *Visualer) HitTest(point mathf.Vec2) *Visualer {
+	if !v.visible {
+		return nil
+	}
+
+	if filter, ok := v.Drawer.(HitFilter); ok && !filter.AllowsHit(point) {
+		return nil
+	}
+
+	// when drawn last we are on top of our children
+	if !v.drawParentFirst && v.Transform.Bounds().Contains(point) {
+		return v
+	}
+
+	order := v.DrawOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		if hit := order[i].HitTest(point); hit != nil {
+			return hit
+		}
+	}
+
+	if v.drawParentFirst && v.Transform.Bounds().Contains(point) {
+		return v
+	}
+
+	return nil
 }
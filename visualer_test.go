@@ -0,0 +1,45 @@
+package igloo_test
+
+import (
+	"testing"
+
+	"github.com/miniscruff/igloo/mathf"
+)
+
+func TestVisualerCameraAttach(t *testing.T) {
+	drawn := []string{}
+	root := newNamedVisual("root", 0, &drawn)
+	child := newNamedVisual("child", 0, &drawn)
+	root.InsertChild(child)
+	root.Transform.SetSize(100, 80)
+	root.Transform.SetNaturalWidth(100)
+	root.Transform.SetNaturalHeight(80)
+	child.Transform.SetPosition(mathf.Vec2{X: 10, Y: 0})
+
+	screen := mathf.NewTransform()
+	screen.SetSize(100, 80)
+	screen.SetNaturalWidth(100)
+	screen.SetNaturalHeight(80)
+	screen.Build(nil)
+
+	root.Layout(screen, screen)
+
+	// attach after our first layout
+	camera := mathf.NewCamera(100, 80)
+	camera.Attach(root.Transform)
+	root.Layout(screen, screen)
+
+	geom := child.Transform.GeoM()
+	if x, y := geom.Apply(0, 0); x != 60 || y != 40 {
+		t.Fatalf("expected: 60, 40, got: %v, %v", x, y)
+	}
+
+	// and detach again
+	root.Transform.SetView(nil)
+	root.Layout(screen, screen)
+
+	geom = child.Transform.GeoM()
+	if x, y := geom.Apply(0, 0); x != 10 || y != 0 {
+		t.Fatalf("expected: 10, 0, got: %v, %v", x, y)
+	}
+}